@@ -1,11 +1,19 @@
 package main
 
 import (
+	"api/src/authentication"
 	"api/src/config"
+	"api/src/database"
+	"api/src/mail"
+	"api/src/migrations"
+	"api/src/repositories"
 	"api/src/router"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
 )
 
 func init() {
@@ -14,10 +22,95 @@ func init() {
 
 func main() {
 	config.Load()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
+
 	fmt.Println(config.StringConnectionDatabase)
 
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if err = authentication.StartKeyRotation(context.Background(), repositories.NewRepositorySigningKeys(db)); err != nil {
+		log.Fatal(err)
+	}
+
+	mailer, err := mail.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	fmt.Printf("Escutando na port %d", config.Port)
-	r := router.Generate()
+	r := router.Generate(db, mailer)
 
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", config.Port), r))
 }
+
+func migrate(args []string) {
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	runner, err := migrations.NewRunner(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		applied, err := runner.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatal("the number of migrations to revert must be a positive number")
+			}
+		}
+
+		reverted, err := runner.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+
+	default:
+		log.Fatalf("unknown migrate command %q, expected up, down [n] or status", command)
+	}
+}
@@ -1,26 +1,138 @@
 package answers
 
 import (
+	"api/src/domain"
+	"api/src/i18n"
+	"api/src/pagination"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 )
 
+// StatusClientClosedRequest is the non-standard status nginx made popular for
+// requests abandoned by the client before an answer was written.
+const StatusClientClosedRequest = 499
+
+type Problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Code     string              `json:"code,omitempty"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []domain.FieldError `json:"errors,omitempty"`
+}
+
+var problemTypes = map[domain.Kind]struct {
+	statusCode int
+	uri        string
+}{
+	domain.KindValidation:   {http.StatusBadRequest, "/problems/validation-error"},
+	domain.KindUnauthorized: {http.StatusUnauthorized, "/problems/unauthorized"},
+	domain.KindForbidden:    {http.StatusForbidden, "/problems/forbidden"},
+	domain.KindNotFound:     {http.StatusNotFound, "/problems/not-found"},
+	domain.KindConflict:     {http.StatusConflict, "/problems/conflict"},
+
+	domain.KindTooManyRequests: {http.StatusTooManyRequests, "/problems/too-many-requests"},
+}
+
 func JSON(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	write(w, "application/json", statusCode, data)
+}
+
+func Err(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		Error(w, r, err)
+		return
+	}
+
+	ProblemJSON(w, r, Problem{
+		Type:   "about:blank",
+		Status: statusCode,
+		Detail: err.Error(),
+	})
+}
+
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	locale := i18n.FromRequest(r)
+	problem := Problem{Type: "about:blank"}
+
+	var domainError *domain.Error
+	problemType, ok := problemTypes[domain.KindOf(err)]
+
+	switch {
+	case ok && errors.As(err, &domainError):
+		problem.Type = problemType.uri
+		problem.Status = problemType.statusCode
+		problem.Code = domainError.Key
+		problem.Detail = domainError.Localize(locale)
+
+		if domainError.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(domainError.RetryAfter.Seconds()))))
+		}
+
+		for _, field := range domainError.Fields {
+			field.Message = i18n.Translate(locale, field.Key, field.Args...)
+			problem.Errors = append(problem.Errors, field)
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		problem.Status = http.StatusServiceUnavailable
+		problem.Code = "request.timeout"
+		w.Header().Set("Retry-After", "1")
+	case errors.Is(err, context.Canceled):
+		problem.Status = StatusClientClosedRequest
+		problem.Code = "request.canceled"
+	default:
+		log.Printf("\n %s %s: %v", r.Method, r.RequestURI, err)
+		problem.Status = http.StatusInternalServerError
+		problem.Code = "request.internal"
+	}
+
+	if problem.Detail == "" {
+		problem.Detail = i18n.Translate(locale, problem.Code)
+	}
+
+	ProblemJSON(w, r, problem)
+}
+
+func ProblemJSON(w http.ResponseWriter, r *http.Request, problem Problem) {
+	locale := i18n.FromRequest(r)
+
+	if problem.Title == "" {
+		key := fmt.Sprintf("http.%d", problem.Status)
+		if problem.Title = i18n.Translate(locale, key); problem.Title == key {
+			problem.Title = http.StatusText(problem.Status)
+		}
+	}
+
+	if problem.Instance == "" {
+		problem.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Language", locale)
+	write(w, "application/problem+json", problem.Status, problem)
+}
+
+func Page(w http.ResponseWriter, r *http.Request, page pagination.Page) {
+	if links := page.Links(r); links != "" {
+		w.Header().Set("Link", links)
+	}
+
+	JSON(w, http.StatusOK, page)
+}
+
+func write(w http.ResponseWriter, contentType string, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(statusCode)
 
 	if data != nil {
 		if err := json.NewEncoder(w).Encode(data); err != nil {
-			log.Fatal(err)
+			log.Printf("\n could not write the answer: %v", err)
 		}
 	}
 }
-
-func Err(w http.ResponseWriter, statusCode int, err error) {
-	JSON(w, statusCode, struct {
-		Err string `json:"err"`
-	}{
-		Err: err.Error(),
-	})
-}
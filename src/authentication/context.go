@@ -0,0 +1,47 @@
+package authentication
+
+import (
+	"api/src/domain"
+	"context"
+	"net/http"
+)
+
+type Principal struct {
+	UserID    uint64
+	SessionID uint64
+	TokenID   string
+	Role      string
+	Scopes    []string
+
+	// Restricted principals come from delegated tokens limited to their Scopes.
+	Restricted bool
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+func PrincipalFromRequest(r *http.Request) (Principal, error) {
+	principal, ok := FromContext(r.Context())
+	if !ok {
+		return Principal{}, domain.Unauthorized("authentication.invalid_token")
+	}
+
+	return principal, nil
+}
+
+func ExtractUserID(r *http.Request) (uint64, error) {
+	principal, err := PrincipalFromRequest(r)
+	if err != nil {
+		return 0, err
+	}
+
+	return principal.UserID, nil
+}
@@ -0,0 +1,296 @@
+package authentication
+
+import (
+	"api/src/config"
+	"api/src/models"
+	"api/src/security"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const keyRefreshInterval = time.Minute
+
+type KeyStore interface {
+	Create(ctx context.Context, key models.SigningKey) error
+	Search(ctx context.Context) ([]models.SigningKey, error)
+	Delete(ctx context.Context, ID string) error
+	Lock(ctx context.Context, function func() error) error
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+}
+
+var keys struct {
+	sync.RWMutex
+	current *signingKey
+	all     map[string]*signingKey
+}
+
+func StartKeyRotation(ctx context.Context, store KeyStore) error {
+	if config.KeySecret == "" {
+		return errors.New("JWT_KEY_SECRET must be set to encrypt the signing keys")
+	}
+
+	if err := RotateKeys(ctx, store); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(keyRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := RotateKeys(ctx, store); err != nil {
+				log.Printf("\n could not rotate signing keys: %v", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func RotateKeys(ctx context.Context, store KeyStore) error {
+	var storedKeys []models.SigningKey
+	if err := store.Lock(ctx, func() error {
+		var err error
+		storedKeys, err = rotateStoredKeys(ctx, store)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	loaded := make(map[string]*signingKey, len(storedKeys))
+	var current *signingKey
+	for _, storedKey := range storedKeys {
+		key, err := parseKey(storedKey)
+		if err != nil {
+			return err
+		}
+
+		loaded[key.id] = key
+		if current == nil {
+			current = key
+		}
+	}
+
+	keys.Lock()
+	keys.current = current
+	keys.all = loaded
+	keys.Unlock()
+
+	return nil
+}
+
+// rotateStoredKeys creates a new key when the current one is due and deletes the
+// ones no longer needed, returning the keys left, newest first. It must run
+// under the store lock so instances do not rotate at the same time.
+func rotateStoredKeys(ctx context.Context, store KeyStore) ([]models.SigningKey, error) {
+	storedKeys, err := store.Search(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys stored before they were encrypted are replaced at once.
+	if len(storedKeys) == 0 || storedKeys[0].Algorithm != config.JWTAlgorithm ||
+		time.Since(storedKeys[0].CreatedAt) >= config.KeyRotation || !security.IsEncrypted(storedKeys[0].PrivateKey) {
+		key, err := generateKey(config.JWTAlgorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		if key.PrivateKey, err = security.Encrypt(config.KeySecret, key.PrivateKey); err != nil {
+			return nil, err
+		}
+
+		if err = store.Create(ctx, key); err != nil {
+			return nil, err
+		}
+
+		storedKeys = append([]models.SigningKey{key}, storedKeys...)
+	}
+
+	kept := make([]models.SigningKey, 0, len(storedKeys))
+	for i, storedKey := range storedKeys {
+		// A key stops signing as soon as a newer one exists, so it only has to stay
+		// around for as long as the tokens it signed before that are still valid.
+		if i > 0 && time.Since(storedKeys[i-1].CreatedAt) > config.AccessTokenDuration+keyRefreshInterval {
+			if err = store.Delete(ctx, storedKey.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		kept = append(kept, storedKey)
+	}
+
+	return kept, nil
+}
+
+func JWKS() JWKSet {
+	keys.RLock()
+	defer keys.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range keys.all {
+		jwk := JWK{
+			KeyID:     key.id,
+			Use:       "sig",
+			Algorithm: key.method.Alg(),
+		}
+
+		switch publicKey := key.privateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func signToken(claims jwt.Claims) (string, error) {
+	keys.RLock()
+	key := keys.current
+	keys.RUnlock()
+
+	if key == nil {
+		return "", errors.New("there is no signing key available")
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.privateKey)
+}
+
+func returnKeyFromVerification(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+
+	keys.RLock()
+	key, ok := keys.all[keyID]
+	keys.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+
+	return key.privateKey.Public(), nil
+}
+
+func generateKey(algorithm string) (models.SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case SigningMethodEdDSA.Alg():
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported signing algorithm %s", algorithm)
+	}
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	if err != nil {
+		return models.SigningKey{}, err
+	}
+
+	return models.SigningKey{
+		ID:         security.HashToken(string(publicDER))[:16],
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+func parseKey(storedKey models.SigningKey) (*signingKey, error) {
+	privateKey := storedKey.PrivateKey
+	if security.IsEncrypted(privateKey) {
+		var err error
+		if privateKey, err = security.Decrypt(config.KeySecret, privateKey); err != nil {
+			return nil, fmt.Errorf("signing key %s cannot be decrypted: %w", storedKey.ID, err)
+		}
+	}
+
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s is not a valid pem block", storedKey.ID)
+	}
+
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := parsedKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s cannot sign", storedKey.ID)
+	}
+
+	method := jwt.GetSigningMethod(storedKey.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported signing algorithm %s", storedKey.Algorithm)
+	}
+
+	return &signingKey{
+		id:         storedKey.ID,
+		method:     method,
+		privateKey: signer,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package authentication
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+const (
+	PermissionListAccounts         = "accounts:list"
+	PermissionSuspendAccounts      = "accounts:suspend"
+	PermissionManageRoles          = "accounts:roles"
+	PermissionDeleteAnyPublication = "publications:delete_any"
+	PermissionModerateReports      = "reports:moderate"
+)
+
+var rolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermissionListAccounts,
+		PermissionSuspendAccounts,
+		PermissionDeleteAnyPublication,
+		PermissionModerateReports,
+	},
+	RoleAdmin: {
+		PermissionListAccounts,
+		PermissionSuspendAccounts,
+		PermissionManageRoles,
+		PermissionDeleteAnyPublication,
+		PermissionModerateReports,
+	},
+}
+
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func (principal Principal) Can(permission string) bool {
+	for _, granted := range rolePermissions[principal.Role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package authentication
+
+// PersonalAccessTokenPrefix tells personal access tokens apart from session
+// JWTs, and makes leaked tokens easy to find with secret scanners.
+const PersonalAccessTokenPrefix = "snp_"
+
+// OAuth tokens are issued to third-party clients by the authorization server.
+const (
+	OAuthAccessTokenPrefix  = "sno_"
+	OAuthRefreshTokenPrefix = "snr_"
+)
+
+const (
+	ScopeUsersRead         = "users:read"
+	ScopeUsersWrite        = "users:write"
+	ScopePublicationsRead  = "publications:read"
+	ScopePublicationsWrite = "publications:write"
+	ScopeCommentsRead      = "comments:read"
+	ScopeCommentsWrite     = "comments:write"
+)
+
+var scopes = []string{
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopePublicationsRead,
+	ScopePublicationsWrite,
+	ScopeCommentsRead,
+	ScopeCommentsWrite,
+}
+
+func ValidScope(scope string) bool {
+	for _, known := range scopes {
+		if known == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasScope is always true for session tokens, which act on behalf of the user
+// with no restriction. Restricted tokens only reach routes that declare one of
+// their scopes, and never the ones that declare none.
+func (principal Principal) HasScope(scope string) bool {
+	if !principal.Restricted {
+		return true
+	}
+
+	for _, granted := range principal.Scopes {
+		if scope != "" && granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package authentication
+
+import "testing"
+
+func TestHasScope(t *testing.T) {
+	restricted := Principal{UserID: 1, Restricted: true, Scopes: []string{ScopePublicationsRead, ScopeCommentsWrite}}
+
+	tests := []struct {
+		name      string
+		principal Principal
+		scope     string
+		want      bool
+	}{
+		{"session on a scoped route", Principal{UserID: 1}, ScopeUsersWrite, true},
+		{"session on a route without scope", Principal{UserID: 1}, "", true},
+		{"granted scope", restricted, ScopePublicationsRead, true},
+		{"another granted scope", restricted, ScopeCommentsWrite, true},
+		{"scope not granted", restricted, ScopePublicationsWrite, false},
+		{"read does not grant write", restricted, ScopeCommentsRead, false},
+		{"route without scope", restricted, "", false},
+		{"restricted without scopes", Principal{UserID: 1, Restricted: true}, ScopeUsersRead, false},
+		{"empty granted scope on a route without scope", Principal{UserID: 1, Restricted: true, Scopes: []string{""}}, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.principal.HasScope(test.scope); got != test.want {
+				t.Errorf("HasScope(%q) = %v, want %v", test.scope, got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{ScopeUsersRead, true},
+		{ScopeCommentsWrite, true},
+		{"", false},
+		{"users", false},
+		{"USERS:READ", false},
+		{"accounts:roles", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scope, func(t *testing.T) {
+			if got := ValidScope(test.scope); got != test.want {
+				t.Errorf("ValidScope(%q) = %v, want %v", test.scope, got, test.want)
+			}
+		})
+	}
+}
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{RoleUser, PermissionListAccounts, false},
+		{RoleUser, PermissionModerateReports, false},
+		{RoleModerator, PermissionSuspendAccounts, true},
+		{RoleModerator, PermissionModerateReports, true},
+		{RoleModerator, PermissionManageRoles, false},
+		{RoleAdmin, PermissionManageRoles, true},
+		{RoleAdmin, PermissionDeleteAnyPublication, true},
+		{"", PermissionListAccounts, false},
+		{"root", PermissionManageRoles, false},
+		{RoleAdmin, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.role+" "+test.permission, func(t *testing.T) {
+			if got := (Principal{UserID: 1, Role: test.role}).Can(test.permission); got != test.want {
+				t.Errorf("%q Can(%q) = %v, want %v", test.role, test.permission, got, test.want)
+			}
+		})
+	}
+}
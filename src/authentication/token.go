@@ -3,7 +3,6 @@ package authentication
 import (
 	"api/src/config"
 	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -12,62 +11,92 @@ import (
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
-func CreateToken(userID uint64) (string, error) {
-	permissions := jwt.MapClaims{}
-	permissions["authorized"] = true
-	permissions["exp"] = time.Now().Add(time.Hour * 6).Unix()
-	permissions["userId"] = userID
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, permissions)
-	return token.SignedString([]byte(config.SecretKey))
+type Claims struct {
+	SessionID uint64   `json:"sid,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	jwt.StandardClaims
 }
 
-func ValidateToken(r *http.Request) error {
-	tokenString := extractToken(r)
-	token, err := jwt.Parse(tokenString, returnKeyFromVerification)
-	if err != nil {
+func (claims Claims) Valid() error {
+	if err := claims.StandardClaims.Valid(); err != nil {
 		return err
 	}
 
-	if _, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return nil
+	if !claims.VerifyIssuer(config.TokenIssuer, true) {
+		return errors.New("the token was not issued by this api")
+	}
+
+	if !claims.VerifyAudience(config.TokenAudience, true) {
+		return errors.New("the token is not intended for this api")
+	}
+
+	if !claims.VerifyNotBefore(time.Now().Unix(), true) {
+		return errors.New("the token is not valid yet")
 	}
 
-	return errors.New("invalid token")
+	if claims.Subject == "" || claims.Id == "" {
+		return errors.New("invalid token")
+	}
+
+	return nil
 }
 
-func ExtractUserID(r *http.Request) (uint64, error) {
-	tokenString := extractToken(r)
-	token, err := jwt.Parse(tokenString, returnKeyFromVerification)
+func (claims Claims) Principal() (Principal, error) {
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
 	if err != nil {
-		return 0, err
+		return Principal{}, errors.New("invalid token subject")
 	}
 
-	if permissions, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, err := strconv.ParseUint(fmt.Sprintf("%.0f", permissions["userId"]), 10, 64)
-		if err != nil {
-			return 0, err
-		}
+	return Principal{
+		UserID:    userID,
+		SessionID: claims.SessionID,
+		TokenID:   claims.Id,
+		Role:      claims.Role,
+		Scopes:    claims.Scopes,
+	}, nil
+}
 
-		return userID, nil
+// CreateToken signs an access token whose ID is recorded on the session it belongs to.
+func CreateToken(userID, sessionID uint64, role, tokenID string) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		SessionID: sessionID,
+		Role:      role,
+		StandardClaims: jwt.StandardClaims{
+			Id:        tokenID,
+			Subject:   strconv.FormatUint(userID, 10),
+			Issuer:    config.TokenIssuer,
+			Audience:  config.TokenAudience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(config.AccessTokenDuration).Unix(),
+		},
 	}
 
-	return 0, errors.New("invalid token")
+	return signToken(claims)
 }
 
-func extractToken(r *http.Request) string {
-	token := r.Header.Get("Authorization")
+func ParseToken(r *http.Request) (Claims, error) {
+	var claims Claims
+	token, err := jwt.ParseWithClaims(BearerToken(r), &claims, returnKeyFromVerification)
+	if err != nil {
+		return Claims{}, err
+	}
 
-	if len(strings.Split(token, " ")) == 2 {
-		return strings.Split(token, " ")[1]
+	if !token.Valid {
+		return Claims{}, errors.New("invalid token")
 	}
 
-	return ""
+	return claims, nil
 }
 
-func returnKeyFromVerification(token *jwt.Token) (interface{}, error) {
-	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, fmt.Errorf("unexpected subscription method %v", token.Header["alg"])
+func BearerToken(r *http.Request) string {
+	token := r.Header.Get("Authorization")
+
+	if len(strings.Split(token, " ")) == 2 {
+		return strings.Split(token, " ")[1]
 	}
 
-	return config.SecretKey, nil
+	return ""
 }
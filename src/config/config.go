@@ -5,14 +5,59 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 var (
-	StringConnectionDatabase = ""
-	Port                     = 0
-	SecretKey                []byte
+	StringConnectionDatabase      = ""
+	DatabaseMaxOpenConnections    = 0
+	DatabaseMaxIdleConnections    = 0
+	DatabaseConnectionMaxLifetime time.Duration
+	DatabaseReadTimeout           time.Duration
+	DatabaseWriteTimeout          time.Duration
+	Port                          = 0
+	AccessTokenDuration           time.Duration
+	RefreshTokenDuration          time.Duration
+	TokenIssuer                   = ""
+	TokenAudience                 = ""
+	JWTAlgorithm                  = ""
+	KeyRotation                   time.Duration
+	KeySecret                     = ""
+	AppURL                        = ""
+	PasswordResetDuration         time.Duration
+	PasswordResetMaxRequests      = 0
+	PasswordResetMaxRequestsPerIP = 0
+	PasswordResetWindow           time.Duration
+	EmailVerificationDuration     time.Duration
+	RequireVerifiedEmail          = false
+	TwoFactorIssuer               = ""
+	TwoFactorChallengeDuration    time.Duration
+	LoginMaxFailures              = 0
+	LoginMaxFailuresPerIP         = 0
+	LoginFailureWindow            time.Duration
+	LoginLockout                  time.Duration
+	LoginMaxLockout               time.Duration
+	TrustProxyHeaders             = false
+	MailDriver                    = ""
+	MailFrom                      = ""
+	MailDirectory                 = ""
+	SMTPHost                      = ""
+	SMTPPort                      = 0
+	SMTPUsername                  = ""
+	SMTPPassword                  = ""
+	OAuthCodeDuration             time.Duration
+	OIDCIssuer                    = ""
+	OIDCClientID                  = ""
+	OIDCClientSecret              = ""
+	OIDCRedirectURL               = ""
+	OIDCScopes                    = ""
+	OIDCLoginDuration             time.Duration
+	MagicLinkEnabled              = false
+	MagicLinkDuration             time.Duration
+	MagicLinkMaxRequests          = 0
+	MagicLinkWindow               time.Duration
 )
 
 func Load() {
@@ -33,5 +78,203 @@ func Load() {
 		os.Getenv("DB_NAME"),
 	)
 
-	SecretKey = []byte(os.Getenv("SECRET_KEY"))
+	DatabaseMaxOpenConnections, err = strconv.Atoi(os.Getenv("DB_MAX_OPEN_CONNECTIONS"))
+	if err != nil {
+		DatabaseMaxOpenConnections = 25
+	}
+
+	DatabaseMaxIdleConnections, err = strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNECTIONS"))
+	if err != nil {
+		DatabaseMaxIdleConnections = 25
+	}
+
+	DatabaseConnectionMaxLifetime, err = time.ParseDuration(os.Getenv("DB_CONNECTION_MAX_LIFETIME"))
+	if err != nil {
+		DatabaseConnectionMaxLifetime = time.Minute * 5
+	}
+
+	DatabaseReadTimeout, err = time.ParseDuration(os.Getenv("DB_READ_TIMEOUT"))
+	if err != nil {
+		DatabaseReadTimeout = time.Second * 5
+	}
+
+	DatabaseWriteTimeout, err = time.ParseDuration(os.Getenv("DB_WRITE_TIMEOUT"))
+	if err != nil {
+		DatabaseWriteTimeout = time.Second * 10
+	}
+
+	AccessTokenDuration, err = time.ParseDuration(os.Getenv("ACCESS_TOKEN_DURATION"))
+	if err != nil {
+		AccessTokenDuration = time.Minute * 15
+	}
+
+	RefreshTokenDuration, err = time.ParseDuration(os.Getenv("REFRESH_TOKEN_DURATION"))
+	if err != nil {
+		RefreshTokenDuration = time.Hour * 24 * 30
+	}
+
+	TokenIssuer = os.Getenv("TOKEN_ISSUER")
+	if TokenIssuer == "" {
+		TokenIssuer = "social-network-api"
+	}
+
+	TokenAudience = os.Getenv("TOKEN_AUDIENCE")
+	if TokenAudience == "" {
+		TokenAudience = "social-network-api"
+	}
+
+	JWTAlgorithm = os.Getenv("JWT_ALGORITHM")
+	if JWTAlgorithm == "" {
+		JWTAlgorithm = "RS256"
+	}
+
+	KeyRotation, err = time.ParseDuration(os.Getenv("JWT_KEY_ROTATION"))
+	if err != nil {
+		KeyRotation = time.Hour * 24 * 30
+	}
+
+	KeySecret = os.Getenv("JWT_KEY_SECRET")
+
+	AppURL = os.Getenv("APP_URL")
+	if AppURL == "" {
+		AppURL = fmt.Sprintf("http://localhost:%d", Port)
+	}
+
+	PasswordResetDuration, err = time.ParseDuration(os.Getenv("PASSWORD_RESET_DURATION"))
+	if err != nil {
+		PasswordResetDuration = time.Hour
+	}
+
+	PasswordResetMaxRequests, err = strconv.Atoi(os.Getenv("PASSWORD_RESET_MAX_REQUESTS"))
+	if err != nil {
+		PasswordResetMaxRequests = 3
+	}
+
+	PasswordResetMaxRequestsPerIP, err = strconv.Atoi(os.Getenv("PASSWORD_RESET_MAX_REQUESTS_PER_IP"))
+	if err != nil {
+		PasswordResetMaxRequestsPerIP = 20
+	}
+
+	PasswordResetWindow, err = time.ParseDuration(os.Getenv("PASSWORD_RESET_WINDOW"))
+	if err != nil {
+		PasswordResetWindow = time.Hour
+	}
+
+	EmailVerificationDuration, err = time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_DURATION"))
+	if err != nil {
+		EmailVerificationDuration = time.Hour * 24
+	}
+
+	RequireVerifiedEmail, err = strconv.ParseBool(os.Getenv("REQUIRE_VERIFIED_EMAIL"))
+	if err != nil {
+		RequireVerifiedEmail = false
+	}
+
+	TwoFactorIssuer = os.Getenv("TWO_FACTOR_ISSUER")
+	if TwoFactorIssuer == "" {
+		TwoFactorIssuer = "Social Network"
+	}
+
+	TwoFactorChallengeDuration, err = time.ParseDuration(os.Getenv("TWO_FACTOR_CHALLENGE_DURATION"))
+	if err != nil {
+		TwoFactorChallengeDuration = time.Minute * 5
+	}
+
+	LoginMaxFailures, err = strconv.Atoi(os.Getenv("LOGIN_MAX_FAILURES"))
+	if err != nil {
+		LoginMaxFailures = 5
+	}
+
+	LoginMaxFailuresPerIP, err = strconv.Atoi(os.Getenv("LOGIN_MAX_FAILURES_PER_IP"))
+	if err != nil {
+		LoginMaxFailuresPerIP = 20
+	}
+
+	LoginFailureWindow, err = time.ParseDuration(os.Getenv("LOGIN_FAILURE_WINDOW"))
+	if err != nil {
+		LoginFailureWindow = time.Hour
+	}
+
+	LoginLockout, err = time.ParseDuration(os.Getenv("LOGIN_LOCKOUT"))
+	if err != nil {
+		LoginLockout = time.Second * 30
+	}
+
+	LoginMaxLockout, err = time.ParseDuration(os.Getenv("LOGIN_MAX_LOCKOUT"))
+	if err != nil {
+		LoginMaxLockout = time.Minute * 15
+	}
+
+	TrustProxyHeaders, err = strconv.ParseBool(os.Getenv("TRUST_PROXY_HEADERS"))
+	if err != nil {
+		TrustProxyHeaders = false
+	}
+
+	MailDriver = os.Getenv("MAIL_DRIVER")
+	if MailDriver == "" {
+		MailDriver = "log"
+	}
+
+	MailFrom = os.Getenv("MAIL_FROM")
+	if MailFrom == "" {
+		MailFrom = "no-reply@localhost"
+	}
+
+	MailDirectory = os.Getenv("MAIL_DIRECTORY")
+	if MailDirectory == "" {
+		MailDirectory = "mails"
+	}
+
+	SMTPHost = os.Getenv("SMTP_HOST")
+	SMTPPort, err = strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if err != nil {
+		SMTPPort = 587
+	}
+
+	SMTPUsername = os.Getenv("SMTP_USERNAME")
+	SMTPPassword = os.Getenv("SMTP_PASSWORD")
+
+	OAuthCodeDuration, err = time.ParseDuration(os.Getenv("OAUTH_CODE_DURATION"))
+	if err != nil {
+		OAuthCodeDuration = time.Minute * 10
+	}
+
+	OIDCIssuer = os.Getenv("OIDC_ISSUER")
+	OIDCClientID = os.Getenv("OIDC_CLIENT_ID")
+	OIDCClientSecret = os.Getenv("OIDC_CLIENT_SECRET")
+
+	OIDCRedirectURL = os.Getenv("OIDC_REDIRECT_URL")
+	if OIDCRedirectURL == "" {
+		OIDCRedirectURL = AppURL + "/login/oidc/callback"
+	}
+
+	OIDCScopes = os.Getenv("OIDC_SCOPES")
+	if OIDCScopes == "" {
+		OIDCScopes = "openid email profile"
+	}
+
+	OIDCLoginDuration, err = time.ParseDuration(os.Getenv("OIDC_LOGIN_DURATION"))
+	if err != nil {
+		OIDCLoginDuration = time.Minute * 10
+	}
+
+	MagicLinkEnabled, err = strconv.ParseBool(os.Getenv("MAGIC_LINK_ENABLED"))
+	if err != nil {
+		MagicLinkEnabled = false
+	}
+
+	MagicLinkDuration, err = time.ParseDuration(os.Getenv("MAGIC_LINK_DURATION"))
+	if err != nil {
+		MagicLinkDuration = time.Minute * 15
+	}
+
+	MagicLinkMaxRequests, err = strconv.Atoi(os.Getenv("MAGIC_LINK_MAX_REQUESTS"))
+	if err != nil {
+		MagicLinkMaxRequests = 3
+	}
+
+	MagicLinkWindow, err = time.ParseDuration(os.Getenv("MAGIC_LINK_WINDOW"))
+	if err != nil {
+		MagicLinkWindow = time.Hour
+	}
 }
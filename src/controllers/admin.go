@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"api/src/repositories"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type Admin struct {
+	users        repositories.Users
+	sessions     repositories.Sessions
+	publications repositories.Publications
+}
+
+func NewControllerAdmin(users repositories.Users, sessions repositories.Sessions, publications repositories.Publications) *Admin {
+	return &Admin{users, sessions, publications}
+}
+
+func (a *Admin) SearchAccounts(w http.ResponseWriter, r *http.Request) {
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	users, err := a.users.SearchAccounts(r.Context(), params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(users, userCursor))
+}
+
+func (a *Admin) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	a.updateSuspension(w, r, true)
+}
+
+func (a *Admin) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	a.updateSuspension(w, r, false)
+}
+
+// updateSuspension only lets admins act on staff accounts. Suspending also
+// revokes every session of the user.
+func (a *Admin) updateSuspension(w http.ResponseWriter, r *http.Request, suspended bool) {
+	principal, err := authentication.PrincipalFromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := a.users.SearchID(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = canSuspend(principal, user); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = a.users.Suspend(r.Context(), userID, suspended); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (a *Admin) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	principal, err := authentication.PrincipalFromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if userID == principal.UserID {
+		answers.Error(w, r, domain.Forbidden("admin.role_self"))
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var user models.User
+	if err = json.Unmarshal(bodyRequest, &user); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if !authentication.ValidRole(user.Role) {
+		answers.Error(w, r, domain.InvalidFields([]domain.FieldError{domain.Field("role", "validation.role_invalid")}))
+		return
+	}
+
+	if _, err = a.users.SearchID(r.Context(), userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = a.users.UpdateRole(r.Context(), userID, user.Role); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// The role travels in the access tokens, so they are revoked to apply it at once.
+	if err = a.sessions.RevokeUser(r.Context(), userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (a *Admin) DeletePublication(w http.ResponseWriter, r *http.Request) {
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err = a.publications.SearchAuthor(r.Context(), publicationID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = a.publications.Delete(r.Context(), publicationID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// canSuspend keeps staff from suspending themselves, and moderators from
+// suspending other staff accounts.
+func canSuspend(principal authentication.Principal, user models.User) error {
+	if user.ID == principal.UserID {
+		return domain.Forbidden("admin.suspend_self")
+	}
+
+	if user.Role != authentication.RoleUser && principal.Role != authentication.RoleAdmin {
+		return domain.Forbidden("admin.suspend_staff")
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BlockUser also ends the follows between both users, which cannot follow each
+// other, nor see each other's profile, publications and comments, until
+// UnblockUser.
+func (u *Users) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, blockerID, err := relationParameters(r, "user.block_self")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if _, err = u.users.SearchID(r.Context(), userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = u.blocks.Block(r.Context(), blockerID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (u *Users) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, blockerID, err := relationParameters(r, "user.block_self")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = u.blocks.Unblock(r.Context(), blockerID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (u *Users) SearchBlocked(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "block.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	users, err := u.blocks.SearchBlocked(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(models.Profiles(users), profileCursor))
+}
+
+// MuteUser hides the user's publications from the feed without unfollowing.
+func (u *Users) MuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, muterID, err := relationParameters(r, "user.mute_self")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if _, err = u.users.SearchID(r.Context(), userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = u.mutes.Mute(r.Context(), muterID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (u *Users) UnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, muterID, err := relationParameters(r, "user.mute_self")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = u.mutes.Unmute(r.Context(), muterID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (u *Users) SearchMuted(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "mute.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	users, err := u.mutes.SearchMuted(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(models.Profiles(users), profileCursor))
+}
+
+// hideBlocked answers the user as not found when either side blocked the other.
+func (u *Users) hideBlocked(r *http.Request, userID uint64) error {
+	viewerID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := u.blocks.Blocked(r.Context(), viewerID, userID)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return domain.NotFound("user.not_found")
+	}
+
+	return nil
+}
+
+// relationParameters reads the target user of a block or mute, which cannot be
+// the authenticated user, answering selfKey otherwise.
+func relationParameters(r *http.Request, selfKey string) (uint64, uint64, error) {
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		return 0, 0, domain.Validation("request.invalid_parameter", "userId")
+	}
+
+	authenticatedID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if userID == authenticatedID {
+		return 0, 0, domain.Forbidden(selfKey)
+	}
+
+	return userID, authenticatedID, nil
+}
@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"api/src/repositories"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type Comments struct {
+	comments     repositories.Comments
+	publications repositories.Publications
+}
+
+func NewControllerComments(comments repositories.Comments, publications repositories.Publications) *Comments {
+	return &Comments{comments, publications}
+}
+
+func (c *Comments) CreateComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var comment models.Comment
+	if err = json.Unmarshal(bodyRequest, &comment); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	comment.PublicationID = publicationID
+	comment.AuthorID = userID
+	comment.Depth = 0
+
+	if err = comment.Prepare(); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if _, err = c.publications.SearchID(r.Context(), publicationID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if comment.ParentID != 0 {
+		parent, err := c.comments.SearchID(r.Context(), comment.ParentID)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			answers.Error(w, r, err)
+			return
+		}
+
+		if err != nil || parent.PublicationID != publicationID {
+			answers.Error(w, r, domain.Validation("comment.invalid_parent"))
+			return
+		}
+
+		if parent.Depth >= models.MaxCommentDepth {
+			answers.Error(w, r, domain.Validation("comment.too_deep", models.MaxCommentDepth))
+			return
+		}
+
+		comment.Depth = parent.Depth + 1
+	}
+
+	comment.ID, err = c.comments.Create(r.Context(), comment)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusCreated, comment)
+}
+
+func (c *Comments) SearchComments(w http.ResponseWriter, r *http.Request) {
+	c.searchThread(w, r, "")
+}
+
+func (c *Comments) SearchReplies(w http.ResponseWriter, r *http.Request) {
+	c.searchThread(w, r, "commentId")
+}
+
+func (c *Comments) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	commentID, err := strconv.ParseUint(parameters["commentId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	commentSavedDatabase, err := c.comments.SearchID(r.Context(), commentID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if commentSavedDatabase.PublicationID != publicationID {
+		answers.Error(w, r, domain.NotFound("comment.not_found"))
+		return
+	}
+
+	if userID != commentSavedDatabase.AuthorID {
+		answers.Error(w, r, domain.Forbidden("comment.forbidden_update"))
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var comment models.Comment
+	if err = json.Unmarshal(bodyRequest, &comment); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = comment.Prepare(); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = c.comments.Update(r.Context(), commentID, comment); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (c *Comments) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	commentID, err := strconv.ParseUint(parameters["commentId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	commentSavedDatabase, err := c.comments.SearchID(r.Context(), commentID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if commentSavedDatabase.PublicationID != publicationID {
+		answers.Error(w, r, domain.NotFound("comment.not_found"))
+		return
+	}
+
+	if userID != commentSavedDatabase.AuthorID {
+		publication, err := c.publications.SearchID(r.Context(), publicationID, userID)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		if userID != publication.AuthorID {
+			answers.Error(w, r, domain.Forbidden("comment.forbidden_delete"))
+			return
+		}
+	}
+
+	if err = c.comments.Delete(r.Context(), commentID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (c *Comments) searchThread(w http.ResponseWriter, r *http.Request, parentParameter string) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var parentID uint64
+	if parentParameter != "" {
+		if parentID, err = strconv.ParseUint(parameters[parentParameter], 10, 64); err != nil {
+			answers.Err(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+	params.Ascending = true
+
+	if _, err = c.publications.SearchID(r.Context(), publicationID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	comments, err := c.comments.SearchPublication(r.Context(), publicationID, parentID, userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(comments, commentCursor))
+}
+
+func commentCursor(item interface{}) pagination.Cursor {
+	comment := item.(models.Comment)
+	return pagination.Cursor{CreatedAt: comment.CreatedAt, ID: comment.ID}
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/i18n"
+	"api/src/mail"
+	"api/src/models"
+	"api/src/repositories"
+	"api/src/security"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+var errInvalidVerification = domain.Validation("email_verification.invalid_token")
+
+type Emails struct {
+	users         repositories.Users
+	verifications repositories.EmailVerifications
+	mailer        mail.Mailer
+}
+
+func NewControllerEmails(users repositories.Users, verifications repositories.EmailVerifications, mailer mail.Mailer) *Emails {
+	return &Emails{users, verifications, mailer}
+}
+
+func (e *Emails) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var verification models.EmailVerification
+	if err = json.Unmarshal(bodyRequest, &verification); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if verification.Token == "" {
+		answers.Error(w, r, domain.InvalidFields([]domain.FieldError{domain.Field("token", "validation.token_required")}))
+		return
+	}
+
+	verification, err = e.verifications.Consume(r.Context(), security.HashToken(verification.Token))
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, errInvalidVerification)
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	user, err := e.users.SearchID(r.Context(), verification.UserID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// The address changed after the link was sent, so it proves nothing about the current one.
+	if user.Email != verification.Email {
+		answers.Error(w, r, errInvalidVerification)
+		return
+	}
+
+	if err = e.users.VerifyEmail(r.Context(), user.ID, user.Email); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (e *Emails) SendVerification(w http.ResponseWriter, r *http.Request) {
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	userIDToken, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if userID != userIDToken {
+		answers.Error(w, r, domain.Forbidden("email.forbidden_verification"))
+		return
+	}
+
+	user, err := e.users.SearchID(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if user.EmailVerified {
+		answers.Error(w, r, domain.Conflict("email.already_verified"))
+		return
+	}
+
+	if err = sendVerification(r.Context(), i18n.FromRequest(r), e.verifications, e.mailer, user.ID, user.Email); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// sendVerification replaces any pending link of the user with a new one for the given address.
+func sendVerification(ctx context.Context, locale string, verifications repositories.EmailVerifications, mailer mail.Mailer, userID uint64, email string) error {
+	if err := verifications.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	token, err := security.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	if _, err = verifications.Create(ctx, models.EmailVerification{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(config.EmailVerificationDuration),
+	}); err != nil {
+		return err
+	}
+
+	deliver(mailer, mail.Message{
+		To:      email,
+		Subject: i18n.Translate(locale, "mail.email_verification.subject"),
+		Body: i18n.Translate(locale, "mail.email_verification.body",
+			fmt.Sprintf("%s/email/verify?token=%s", config.AppURL, url.QueryEscape(token)),
+			config.EmailVerificationDuration,
+		),
+	})
+
+	return nil
+}
+
+// deliver sends the message in the background so the response time does not
+// depend on the mail server and cannot reveal whether an address is registered.
+func deliver(mailer mail.Mailer, message mail.Message) {
+	go func() {
+		if err := mailer.Send(context.Background(), message); err != nil {
+			log.Printf("sending email %q to %s: %v", message.Subject, message.To, err)
+		}
+	}()
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// UpdatePrivacy makes the account private or public again, in which case the
+// pending follow requests are approved.
+func (u *Users) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "user.forbidden_update")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var user models.User
+	if err = json.Unmarshal(bodyRequest, &user); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = u.users.UpdatePrivate(r.Context(), userID, user.Private); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (u *Users) SearchFollowRequests(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "follow_request.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	requests, err := u.users.SearchFollowRequests(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(requests, followRequestCursor))
+}
+
+func (u *Users) ApproveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, followerID, err := followRequestParameters(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	approved, err := u.users.ApproveFollowRequest(r.Context(), userID, followerID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !approved {
+		answers.Error(w, r, domain.NotFound("follow_request.not_found"))
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (u *Users) RejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, followerID, err := followRequestParameters(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	rejected, err := u.users.DeleteFollowRequest(r.Context(), userID, followerID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !rejected {
+		answers.Error(w, r, domain.NotFound("follow_request.not_found"))
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func followRequestParameters(r *http.Request) (uint64, uint64, error) {
+	userID, err := authorizeOwner(r, "follow_request.forbidden")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	followerID, err := strconv.ParseUint(mux.Vars(r)["followerId"], 10, 64)
+	if err != nil {
+		return 0, 0, domain.Validation("request.invalid_parameter", "followerId")
+	}
+
+	return userID, followerID, nil
+}
+
+func followRequestCursor(item interface{}) pagination.Cursor {
+	request := item.(models.FollowRequest)
+	return pagination.Cursor{CreatedAt: request.CreatedAt, ID: request.FollowerID}
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"net/http"
+)
+
+type Keys struct{}
+
+func NewControllerKeys() *Keys {
+	return &Keys{}
+}
+
+func (k *Keys) SearchKeys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	answers.JSON(w, http.StatusOK, authentication.JWKS())
+}
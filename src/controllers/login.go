@@ -3,52 +3,474 @@ package controllers
 import (
 	"api/src/answers"
 	"api/src/authentication"
-	"api/src/database"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/mail"
 	"api/src/models"
+	"api/src/oidc"
 	"api/src/repositories"
 	"api/src/security"
+	"context"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"time"
 )
 
-func Login(w http.ResponseWriter, r *http.Request) {
+var (
+	errInvalidCredentials = domain.Unauthorized("login.invalid_credentials")
+	errSuspended          = domain.Forbidden("user.suspended")
+)
+
+const (
+	maxChallengeAttempts = 5
+
+	// maxEmailLength is the size of the email columns; longer addresses cannot
+	// belong to any account.
+	maxEmailLength = 50
+)
+
+type Login struct {
+	users         repositories.Users
+	sessions      repositories.Sessions
+	challenges    repositories.LoginChallenges
+	recoveryCodes repositories.RecoveryCodes
+	attempts      repositories.LoginAttempts
+	oidcLogins    repositories.OIDCLogins
+	identities    repositories.UserIdentities
+	provider      *oidc.Provider
+	magicLinks    repositories.MagicLinks
+	mailer        mail.Mailer
+}
+
+func NewControllerLogin(users repositories.Users, sessions repositories.Sessions, challenges repositories.LoginChallenges, recoveryCodes repositories.RecoveryCodes, attempts repositories.LoginAttempts, oidcLogins repositories.OIDCLogins, identities repositories.UserIdentities, provider *oidc.Provider, magicLinks repositories.MagicLinks, mailer mail.Mailer) *Login {
+	return &Login{users, sessions, challenges, recoveryCodes, attempts, oidcLogins, identities, provider, magicLinks, mailer}
+}
+
+func (l *Login) Login(w http.ResponseWriter, r *http.Request) {
 	bodyRequest, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		answers.Err(w, http.StatusUnprocessableEntity, err)
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	var user models.User
 	if err = json.Unmarshal(bodyRequest, &user); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	db, err := database.Connect()
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	user.Email = truncate(user.Email, maxEmailLength)
+	if err = l.throttle(r, user.Email); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	userSaveDatabase, err := repository.SearchEmail(user.Email)
+	userSaveDatabase, err := l.users.SearchEmail(r.Context(), user.Email)
+	if errors.Is(err, domain.ErrNotFound) {
+		security.PasswordCheckUnknown(user.Password)
+		if err = l.record(r, 0, user.Email, false, "unknown_email"); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.Error(w, r, errInvalidCredentials)
+		return
+	}
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if err = security.PasswordCheck(userSaveDatabase.Password, user.Password); err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		if err = l.record(r, userSaveDatabase.ID, user.Email, false, "invalid_password"); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.Error(w, r, errInvalidCredentials)
+		return
+	}
+
+	if userSaveDatabase.SuspendedAt != nil {
+		answers.Error(w, r, errSuspended)
 		return
 	}
 
-	token, err := authentication.CreateToken(userSaveDatabase.ID)
+	twoFactor, err := l.users.SearchTwoFactor(r.Context(), userSaveDatabase.ID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
+		return
+	}
+
+	if twoFactor.Enabled {
+		challenge, err := l.createChallenge(r.Context(), userSaveDatabase.ID)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.JSON(w, http.StatusOK, challenge)
+		return
+	}
+
+	if err = l.record(r, userSaveDatabase.ID, user.Email, true, "password"); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
-	w.Write([]byte(token))
+	authenticationData, err := l.createSession(r, userSaveDatabase)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, authenticationData)
+}
+
+func (l *Login) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	code, err := readTwoFactorCode(r)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var violations []domain.FieldError
+	if code.ChallengeToken == "" {
+		violations = append(violations, domain.Field("challenge_token", "validation.token_required"))
+	}
+	if code.Code == "" && code.RecoveryCode == "" {
+		violations = append(violations, domain.Field("code", "validation.code_required"))
+	}
+	if err = domain.InvalidFields(violations); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	challenge, err := l.challenges.SearchToken(r.Context(), security.HashToken(code.ChallengeToken))
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, domain.Unauthorized("two_factor.invalid_challenge"))
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if challenge.Attempts >= maxChallengeAttempts {
+		answers.Error(w, r, domain.Unauthorized("two_factor.invalid_challenge"))
+		return
+	}
+
+	user, err := l.users.SearchID(r.Context(), challenge.UserID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		answers.Error(w, r, errSuspended)
+		return
+	}
+
+	if err = l.throttle(r, user.Email); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	var valid bool
+	if code.RecoveryCode != "" {
+		valid, err = l.recoveryCodes.Use(r.Context(), challenge.UserID, security.HashRecoveryCode(code.RecoveryCode))
+	} else {
+		var twoFactor models.TwoFactor
+		if twoFactor, err = l.users.SearchTwoFactor(r.Context(), challenge.UserID); err == nil {
+			valid, err = verifyTOTP(r.Context(), l.users, challenge.UserID, twoFactor.Secret, code.Code)
+		}
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !valid {
+		if err = l.challenges.Fail(r.Context(), challenge.ID); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		if err = l.record(r, user.ID, user.Email, false, "invalid_two_factor"); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.Error(w, r, errInvalidTwoFactorCode)
+		return
+	}
+
+	consumed, err := l.challenges.Consume(r.Context(), challenge.ID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !consumed {
+		answers.Error(w, r, domain.Unauthorized("two_factor.invalid_challenge"))
+		return
+	}
+
+	if err = l.record(r, user.ID, user.Email, true, "two_factor"); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	authenticationData, err := l.createSession(r, user)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, authenticationData)
+}
+
+func (l *Login) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var authenticationData models.Authentication
+	if err = json.Unmarshal(bodyRequest, &authenticationData); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if authenticationData.RefreshToken == "" {
+		answers.Error(w, r, domain.Validation("token.refresh_required"))
+		return
+	}
+
+	refreshTokenHash := security.HashToken(authenticationData.RefreshToken)
+	session, err := l.sessions.SearchRefreshToken(r.Context(), refreshTokenHash)
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, l.replayedRefreshToken(r.Context(), refreshTokenHash))
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	user, err := l.users.SearchID(r.Context(), session.UserID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		answers.Error(w, r, errSuspended)
+		return
+	}
+
+	refreshToken, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	tokenID, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	expiresAt := time.Now().Add(config.RefreshTokenDuration)
+	rotated, err := l.sessions.Rotate(r.Context(), session.ID, refreshTokenHash, security.HashToken(refreshToken), tokenID, expiresAt)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// The token was already used by another refresh, so it may have leaked and
+	// the whole session goes.
+	if !rotated {
+		if err = l.sessions.Revoke(r.Context(), session.ID); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.Error(w, r, domain.Unauthorized("token.refresh_invalid"))
+		return
+	}
+
+	accessToken, err := authentication.CreateToken(session.UserID, session.ID, user.Role, tokenID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, models.Authentication{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(config.AccessTokenDuration.Seconds()),
+	})
+}
+
+// replayedRefreshToken revokes the session of a refresh token that was already
+// rotated: either the token leaked or whoever rotated it did, and the real user
+// cannot be told apart from the thief.
+func (l *Login) replayedRefreshToken(ctx context.Context, refreshTokenHash string) error {
+	sessionID, err := l.sessions.SearchRetiredRefreshToken(ctx, refreshTokenHash)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Unauthorized("token.refresh_invalid")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = l.sessions.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return domain.Unauthorized("token.refresh_invalid")
+}
+
+func (l *Login) Logout(w http.ResponseWriter, r *http.Request) {
+	principal, err := authentication.PrincipalFromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = l.sessions.Revoke(r.Context(), principal.SessionID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (l *Login) createSession(r *http.Request, user models.User) (models.Authentication, error) {
+	refreshToken, err := security.GenerateToken()
+	if err != nil {
+		return models.Authentication{}, err
+	}
+
+	tokenID, err := security.GenerateToken()
+	if err != nil {
+		return models.Authentication{}, err
+	}
+
+	sessionID, err := l.sessions.Create(r.Context(), models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: security.HashToken(refreshToken),
+		TokenID:          tokenID,
+		UserAgent:        security.ClientUserAgent(r),
+		IP:               security.ClientIP(r),
+		ExpiresAt:        time.Now().Add(config.RefreshTokenDuration),
+	})
+	if err != nil {
+		return models.Authentication{}, err
+	}
+
+	accessToken, err := authentication.CreateToken(user.ID, sessionID, user.Role, tokenID)
+	if err != nil {
+		return models.Authentication{}, err
+	}
+
+	return models.Authentication{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(config.AccessTokenDuration.Seconds()),
+	}, nil
+}
+
+// createChallenge is answered instead of tokens when the account has a second
+// factor, and must be exchanged together with a code at LoginTwoFactor.
+func (l *Login) createChallenge(ctx context.Context, userID uint64) (models.LoginChallenge, error) {
+	token, err := security.GenerateToken()
+	if err != nil {
+		return models.LoginChallenge{}, err
+	}
+
+	challenge := models.LoginChallenge{
+		UserID:            userID,
+		TokenHash:         security.HashToken(token),
+		ExpiresAt:         time.Now().Add(config.TwoFactorChallengeDuration),
+		Token:             token,
+		ExpiresIn:         int64(config.TwoFactorChallengeDuration.Seconds()),
+		TwoFactorRequired: true,
+	}
+
+	if challenge.ID, err = l.challenges.Create(ctx, challenge); err != nil {
+		return models.LoginChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// throttle rejects the attempt while the account or the client address is
+// locked out after too many consecutive failures.
+func (l *Login) throttle(r *http.Request, email string) error {
+	since := time.Now().Add(-config.LoginFailureWindow)
+
+	emailFailures, err := l.attempts.SearchEmailFailures(r.Context(), email, since)
+	if err != nil {
+		return err
+	}
+
+	ipFailures, err := l.attempts.SearchIPFailures(r.Context(), security.ClientIP(r), since)
+	if err != nil {
+		return err
+	}
+
+	wait := lockout(emailFailures, config.LoginMaxFailures)
+	if ipWait := lockout(ipFailures, config.LoginMaxFailuresPerIP); ipWait > wait {
+		wait = ipWait
+	}
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err = l.record(r, 0, email, false, "locked"); err != nil {
+		return err
+	}
+
+	return domain.TooManyRequests(wait, "login.locked", int(math.Ceil(wait.Seconds())))
+}
+
+func (l *Login) record(r *http.Request, userID uint64, email string, success bool, reason string) error {
+	return l.attempts.Create(r.Context(), models.LoginAttempt{
+		UserID:    userID,
+		Email:     email,
+		IP:        security.ClientIP(r),
+		UserAgent: security.ClientUserAgent(r),
+		Success:   success,
+		Reason:    reason,
+	})
+}
+
+// lockout doubles the wait for every failure past the threshold, counted from
+// the last failure and capped at LoginMaxLockout.
+func lockout(failures models.LoginFailures, threshold int) time.Duration {
+	if failures.Count < threshold {
+		return 0
+	}
+
+	exponent := failures.Count - threshold
+	if exponent > 16 {
+		exponent = 16
+	}
+
+	delay := config.LoginLockout << uint(exponent)
+	if delay > config.LoginMaxLockout {
+		delay = config.LoginMaxLockout
+	}
+
+	return time.Until(failures.LastAt.Add(delay))
 }
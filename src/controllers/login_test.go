@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"api/src/authentication"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/repositories"
+	"api/src/security"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSessions keeps sessions in memory with the same rotation rules as the
+// database: only the current refresh token rotates, and it is then retired.
+type fakeSessions struct {
+	repositories.Sessions
+
+	current map[uint64]string
+	retired map[string]uint64
+	revoked map[uint64]bool
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{current: map[uint64]string{}, retired: map[string]uint64{}, revoked: map[uint64]bool{}}
+}
+
+func (f *fakeSessions) SearchRefreshToken(ctx context.Context, refreshTokenHash string) (models.Session, error) {
+	for ID, hash := range f.current {
+		if hash == refreshTokenHash && !f.revoked[ID] {
+			return models.Session{ID: ID, UserID: 1}, nil
+		}
+	}
+
+	return models.Session{}, domain.NotFound("session.not_found")
+}
+
+func (f *fakeSessions) SearchRetiredRefreshToken(ctx context.Context, refreshTokenHash string) (uint64, error) {
+	ID, ok := f.retired[refreshTokenHash]
+	if !ok {
+		return 0, domain.NotFound("session.not_found")
+	}
+
+	return ID, nil
+}
+
+func (f *fakeSessions) Rotate(ctx context.Context, ID uint64, previousHash, refreshTokenHash, tokenID string, expiresAt time.Time) (bool, error) {
+	if f.revoked[ID] || f.current[ID] != previousHash {
+		return false, nil
+	}
+
+	f.current[ID] = refreshTokenHash
+	f.retired[previousHash] = ID
+	return true, nil
+}
+
+func (f *fakeSessions) Revoke(ctx context.Context, ID uint64) error {
+	f.revoked[ID] = true
+	return nil
+}
+
+type fakeUsers struct {
+	repositories.Users
+
+	saved map[uint64]models.User
+}
+
+func (f *fakeUsers) SearchID(ctx context.Context, ID uint64) (models.User, error) {
+	user, ok := f.saved[ID]
+	if !ok {
+		return models.User{}, domain.NotFound("user.not_found")
+	}
+
+	return user, nil
+}
+
+// fakeKeyStore lets tests sign access tokens without a database.
+type fakeKeyStore struct {
+	keys []models.SigningKey
+}
+
+func (f *fakeKeyStore) Create(ctx context.Context, key models.SigningKey) error {
+	f.keys = append([]models.SigningKey{key}, f.keys...)
+	return nil
+}
+
+func (f *fakeKeyStore) Search(ctx context.Context) ([]models.SigningKey, error) {
+	return f.keys, nil
+}
+
+func (f *fakeKeyStore) Delete(ctx context.Context, ID string) error {
+	return nil
+}
+
+func (f *fakeKeyStore) Lock(ctx context.Context, function func() error) error {
+	return function()
+}
+
+var signingKeyOnce sync.Once
+
+func loadSigningKey(t *testing.T) {
+	signingKeyOnce.Do(func() {
+		config.JWTAlgorithm = "RS256"
+		config.KeyRotation = time.Hour
+		config.KeySecret = "test secret"
+		config.AccessTokenDuration = time.Minute
+
+		if err := authentication.RotateKeys(context.Background(), &fakeKeyStore{}); err != nil {
+			t.Fatalf("RotateKeys: %v", err)
+		}
+	})
+}
+
+func refresh(login *Login, refreshToken string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/refresh", strings.NewReader(`{"refresh_token":"`+refreshToken+`"}`))
+	login.RefreshToken(w, r)
+	return w
+}
+
+func TestRefreshTokenReplayRevokesTheSession(t *testing.T) {
+	loadSigningKey(t)
+
+	sessions := newFakeSessions()
+	sessions.current[7] = security.HashToken("first")
+	users := &fakeUsers{saved: map[uint64]models.User{1: {ID: 1, Role: authentication.RoleUser}}}
+	login := &Login{users: users, sessions: sessions}
+
+	w := refresh(login, "first")
+	if w.Code != http.StatusOK {
+		t.Fatalf("first refresh answered %d: %s", w.Code, w.Body)
+	}
+
+	var rotated models.Authentication
+	if err := json.Unmarshal(w.Body.Bytes(), &rotated); err != nil || rotated.RefreshToken == "" || rotated.RefreshToken == "first" {
+		t.Fatalf("first refresh answered %s, want a new refresh token", w.Body)
+	}
+
+	// Whoever presents the old token again, the session cannot be trusted anymore.
+	if w = refresh(login, "first"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("replaying the rotated token answered %d, want 401", w.Code)
+	}
+
+	if !sessions.revoked[7] {
+		t.Fatal("replaying the rotated token left the session open")
+	}
+
+	if w = refresh(login, rotated.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("the current token of a revoked session answered %d, want 401", w.Code)
+	}
+}
+
+func TestRefreshTokenUnknown(t *testing.T) {
+	sessions := newFakeSessions()
+	sessions.current[7] = security.HashToken("first")
+	login := &Login{users: &fakeUsers{}, sessions: sessions}
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		wantStatus   int
+	}{
+		{"missing", "", http.StatusBadRequest},
+		{"never issued", "guessed", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if w := refresh(login, test.refreshToken); w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, test.wantStatus, w.Body)
+			}
+
+			if sessions.revoked[7] {
+				t.Error("an unknown token revoked an unrelated session")
+			}
+		})
+	}
+}
+
+type fakeLoginAttempts struct {
+	repositories.LoginAttempts
+
+	emailFailures models.LoginFailures
+	ipFailures    models.LoginFailures
+	recorded      []models.LoginAttempt
+}
+
+func (f *fakeLoginAttempts) Create(ctx context.Context, attempt models.LoginAttempt) error {
+	f.recorded = append(f.recorded, attempt)
+	return nil
+}
+
+func (f *fakeLoginAttempts) SearchEmailFailures(ctx context.Context, email string, since time.Time) (models.LoginFailures, error) {
+	return f.emailFailures, nil
+}
+
+func (f *fakeLoginAttempts) SearchIPFailures(ctx context.Context, ip string, since time.Time) (models.LoginFailures, error) {
+	return f.ipFailures, nil
+}
+
+func setLockoutConfig(t *testing.T) {
+	maxFailures, maxFailuresPerIP, lockoutDuration, maxLockout :=
+		config.LoginMaxFailures, config.LoginMaxFailuresPerIP, config.LoginLockout, config.LoginMaxLockout
+	t.Cleanup(func() {
+		config.LoginMaxFailures, config.LoginMaxFailuresPerIP, config.LoginLockout, config.LoginMaxLockout =
+			maxFailures, maxFailuresPerIP, lockoutDuration, maxLockout
+	})
+
+	config.LoginMaxFailures = 5
+	config.LoginMaxFailuresPerIP = 20
+	config.LoginLockout = 30 * time.Second
+	config.LoginMaxLockout = 15 * time.Minute
+}
+
+func TestLockout(t *testing.T) {
+	setLockoutConfig(t)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		failures models.LoginFailures
+		want     time.Duration
+	}{
+		{"no failures", models.LoginFailures{}, 0},
+		{"below the threshold", models.LoginFailures{Count: 4, LastAt: now}, 0},
+		{"at the threshold", models.LoginFailures{Count: 5, LastAt: now}, 30 * time.Second},
+		{"one past the threshold", models.LoginFailures{Count: 6, LastAt: now}, time.Minute},
+		{"four past the threshold", models.LoginFailures{Count: 9, LastAt: now}, 8 * time.Minute},
+		{"capped", models.LoginFailures{Count: 10, LastAt: now}, 15 * time.Minute},
+		{"far past the threshold", models.LoginFailures{Count: 1000, LastAt: now}, 15 * time.Minute},
+		{"partly waited out", models.LoginFailures{Count: 6, LastAt: now.Add(-40 * time.Second)}, 20 * time.Second},
+		{"waited out", models.LoginFailures{Count: 6, LastAt: now.Add(-2 * time.Minute)}, -time.Minute},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := lockout(test.failures, 5)
+			if got > test.want || got < test.want-time.Second {
+				t.Errorf("lockout(%d failures) = %v, want %v", test.failures.Count, got, test.want)
+			}
+		})
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	setLockoutConfig(t)
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		emailFailures models.LoginFailures
+		ipFailures    models.LoginFailures
+		wantWait      time.Duration
+	}{
+		{"no failures", models.LoginFailures{}, models.LoginFailures{}, 0},
+		{"account below the threshold", models.LoginFailures{Count: 4, LastAt: now}, models.LoginFailures{Count: 4, LastAt: now}, 0},
+		{"account locked", models.LoginFailures{Count: 5, LastAt: now}, models.LoginFailures{Count: 5, LastAt: now}, 30 * time.Second},
+		{"address below its own threshold", models.LoginFailures{}, models.LoginFailures{Count: 19, LastAt: now}, 0},
+		{"address locked", models.LoginFailures{}, models.LoginFailures{Count: 21, LastAt: now}, time.Minute},
+		{"longest wait wins", models.LoginFailures{Count: 7, LastAt: now}, models.LoginFailures{Count: 20, LastAt: now}, 2 * time.Minute},
+		{"lockout waited out", models.LoginFailures{Count: 5, LastAt: now.Add(-time.Minute)}, models.LoginFailures{}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attempts := &fakeLoginAttempts{emailFailures: test.emailFailures, ipFailures: test.ipFailures}
+			login := &Login{attempts: attempts}
+
+			err := login.throttle(httptest.NewRequest(http.MethodPost, "/login", nil), "user@example.com")
+			if test.wantWait == 0 {
+				if err != nil || len(attempts.recorded) != 0 {
+					t.Fatalf("throttle = %v with %d recorded attempts, want the attempt let through", err, len(attempts.recorded))
+				}
+				return
+			}
+
+			var domainError *domain.Error
+			if !errors.As(err, &domainError) || domainError.Kind != domain.KindTooManyRequests {
+				t.Fatalf("throttle = %v, want too many requests", err)
+			}
+
+			if wait := domainError.RetryAfter; wait > test.wantWait || wait < test.wantWait-time.Second {
+				t.Errorf("retry after %v, want %v", wait, test.wantWait)
+			}
+
+			// Refused attempts are audited but must not extend the lockout.
+			if len(attempts.recorded) != 1 || attempts.recorded[0].Reason != "locked" || attempts.recorded[0].Success {
+				t.Errorf("recorded %+v, want one locked attempt", attempts.recorded)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/i18n"
+	"api/src/mail"
+	"api/src/models"
+	"api/src/security"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SendMagicLink always answers 204, and rate limits unknown emails like
+// registered ones, so the endpoint cannot be used to find out which emails
+// are registered.
+func (l *Login) SendMagicLink(w http.ResponseWriter, r *http.Request) {
+	if !config.MagicLinkEnabled {
+		answers.Error(w, r, domain.NotFound("magic_link.disabled"))
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var link models.MagicLink
+	if err = json.Unmarshal(bodyRequest, &link); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	link.Email = truncate(strings.TrimSpace(link.Email), maxEmailLength)
+	if link.Email == "" {
+		answers.Error(w, r, domain.InvalidFields([]domain.FieldError{domain.Field("email", "validation.email_required")}))
+		return
+	}
+
+	requests, err := l.magicLinks.SearchEmailRequests(r.Context(), link.Email, time.Now().Add(-config.MagicLinkWindow))
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = throttle(requests, config.MagicLinkMaxRequests, config.MagicLinkWindow, "magic_link.too_many"); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	user, err := l.users.SearchEmail(r.Context(), link.Email)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, err)
+		return
+	}
+
+	var token string
+	if err == nil && user.SuspendedAt == nil {
+		if token, err = security.GenerateToken(); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		link.UserID = user.ID
+		link.TokenHash = security.HashToken(token)
+	}
+
+	link.ExpiresAt = time.Now().Add(config.MagicLinkDuration)
+	if _, err = l.magicLinks.Create(r.Context(), link); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if token != "" {
+		locale := i18n.FromRequest(r)
+		deliver(l.mailer, mail.Message{
+			To:      user.Email,
+			Subject: i18n.Translate(locale, "mail.magic_link.subject"),
+			Body: i18n.Translate(locale, "mail.magic_link.body",
+				fmt.Sprintf("%s/login/magic?token=%s", config.AppURL, url.QueryEscape(token)),
+				config.MagicLinkDuration,
+			),
+		})
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// LoginMagicLink exchanges the token of a link for the same tokens, or
+// two-factor challenge, as Login. Following the link also proves the user
+// owns the email.
+func (l *Login) LoginMagicLink(w http.ResponseWriter, r *http.Request) {
+	if !config.MagicLinkEnabled {
+		answers.Error(w, r, domain.NotFound("magic_link.disabled"))
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var link models.MagicLink
+	if err = json.Unmarshal(bodyRequest, &link); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if link.Token == "" {
+		answers.Error(w, r, domain.InvalidFields([]domain.FieldError{domain.Field("token", "validation.token_required")}))
+		return
+	}
+
+	link, err = l.magicLinks.Consume(r.Context(), security.HashToken(link.Token))
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, domain.Unauthorized("magic_link.invalid_token"))
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	user, err := l.users.SearchID(r.Context(), link.UserID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// A link sent before the email changed no longer proves who is signing in.
+	if !strings.EqualFold(user.Email, link.Email) {
+		answers.Error(w, r, domain.Unauthorized("magic_link.invalid_token"))
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		answers.Error(w, r, errSuspended)
+		return
+	}
+
+	if !user.EmailVerified {
+		if err = l.users.VerifyEmail(r.Context(), user.ID, user.Email); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+	}
+
+	twoFactor, err := l.users.SearchTwoFactor(r.Context(), user.ID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if twoFactor.Enabled {
+		challenge, err := l.createChallenge(r.Context(), user.ID)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.JSON(w, http.StatusOK, challenge)
+		return
+	}
+
+	if err = l.record(r, user.ID, user.Email, true, "magic_link"); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	authenticationData, err := l.createSession(r, user)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, authenticationData)
+}
+
+// throttle refuses a request once max of them were made within the window,
+// until the first one leaves it.
+func throttle(requests models.Requests, max int, window time.Duration, key string) error {
+	if requests.Count < max {
+		return nil
+	}
+
+	wait := time.Until(requests.FirstAt.Add(window))
+	return domain.TooManyRequests(wait, key, int(math.Ceil(wait.Seconds())))
+}
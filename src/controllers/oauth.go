@@ -0,0 +1,719 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/i18n"
+	"api/src/models"
+	"api/src/pagination"
+	"api/src/repositories"
+	"api/src/security"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// errOAuthInvalidClient is kept apart from domain errors because the token,
+// introspection and revocation endpoints answer in the RFC 6749 format.
+var errOAuthInvalidClient = errors.New("invalid_client")
+
+type OAuth struct {
+	clients  repositories.OAuthClients
+	codes    repositories.OAuthCodes
+	consents repositories.OAuthConsents
+	tokens   repositories.OAuthTokens
+	users    repositories.Users
+}
+
+func NewControllerOAuth(clients repositories.OAuthClients, codes repositories.OAuthCodes, consents repositories.OAuthConsents, tokens repositories.OAuthTokens, users repositories.Users) *OAuth {
+	return &OAuth{clients, codes, consents, tokens, users}
+}
+
+// RegisterClient answers the only copy of the secret of confidential clients,
+// which is stored hashed.
+func (o *OAuth) RegisterClient(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "oauth.forbidden_client")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var client models.OAuthClient
+	if err = json.Unmarshal(bodyRequest, &client); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = client.Prepare(); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if client.ClientID, err = security.GenerateToken(); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	client.ClientSecret = ""
+	if client.Confidential {
+		if client.ClientSecret, err = security.GenerateToken(); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+		client.SecretHash = security.HashToken(client.ClientSecret)
+	}
+
+	client.OwnerID = userID
+	client.CreatedAt = time.Now()
+	client.ID, err = o.clients.Create(r.Context(), client)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusCreated, client)
+}
+
+func (o *OAuth) SearchClients(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "oauth.forbidden_client")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	clients, err := o.clients.SearchOwner(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(clients, clientCursor))
+}
+
+func (o *OAuth) DeleteClient(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "oauth.forbidden_client")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	deleted, err := o.clients.Delete(r.Context(), mux.Vars(r)["clientId"], userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !deleted {
+		answers.Error(w, r, domain.NotFound("oauth.client_not_found"))
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// Authorize validates an authorization request and describes it, so the
+// front end can render the consent screen or skip it when the user already
+// agreed to every scope.
+func (o *OAuth) Authorize(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	authorization := models.OAuthAuthorization{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	}
+
+	client, err := o.validateAuthorization(r.Context(), &authorization)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	consent, err := o.consents.Search(r.Context(), userID, client.ID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, err)
+		return
+	}
+
+	authorization.ConsentRequired = !consent.Covers(authorization.Scopes)
+	answers.JSON(w, http.StatusOK, authorization)
+}
+
+// Decide records the decision of the user and answers where the front end
+// must send the browser back to, with either the code or access_denied.
+func (o *OAuth) Decide(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var authorization models.OAuthAuthorization
+	if err = json.Unmarshal(bodyRequest, &authorization); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	client, err := o.validateAuthorization(r.Context(), &authorization)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	values := url.Values{}
+	if authorization.State != "" {
+		values.Set("state", authorization.State)
+	}
+
+	if !authorization.Approve {
+		values.Set("error", "access_denied")
+		authorization.RedirectTo = redirectURL(authorization.RedirectURI, values)
+		answers.JSON(w, http.StatusOK, authorization)
+		return
+	}
+
+	consent, err := o.consents.Search(r.Context(), userID, client.ID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, err)
+		return
+	}
+
+	consent.UserID = userID
+	consent.ClientID = client.ID
+	consent.Scopes = mergeScopes(consent.Scopes, authorization.Scopes)
+	if err = o.consents.Save(r.Context(), consent); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	code, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if _, err = o.codes.Create(r.Context(), models.OAuthCode{
+		ClientID:      client.ID,
+		UserID:        userID,
+		CodeHash:      security.HashToken(code),
+		RedirectURI:   authorization.RedirectURI,
+		Scopes:        authorization.Scopes,
+		CodeChallenge: authorization.CodeChallenge,
+		ExpiresAt:     time.Now().Add(config.OAuthCodeDuration),
+	}); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	values.Set("code", code)
+	authorization.RedirectTo = redirectURL(authorization.RedirectURI, values)
+	answers.JSON(w, http.StatusOK, authorization)
+}
+
+// Token exchanges authorization codes and refresh tokens (RFC 6749 section 4.1.3
+// and 6). Refresh tokens are rotated on every use.
+func (o *OAuth) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", "body")
+		return
+	}
+
+	client, err := o.authenticateClient(r)
+	if err != nil {
+		o.clientError(w, r, err)
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		o.exchangeCode(w, r, client)
+	case "refresh_token":
+		o.refresh(w, r, client)
+	default:
+		oauthError(w, r, http.StatusBadRequest, "unsupported_grant_type", "oauth.unsupported_grant_type")
+	}
+}
+
+func (o *OAuth) exchangeCode(w http.ResponseWriter, r *http.Request, client models.OAuthClient) {
+	for _, parameter := range []string{"code", "redirect_uri", "code_verifier"} {
+		if r.PostForm.Get(parameter) == "" {
+			oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", parameter)
+			return
+		}
+	}
+
+	code, err := o.codes.SearchCode(r.Context(), security.HashToken(r.PostForm.Get("code")))
+	if errors.Is(err, domain.ErrNotFound) {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if code.ClientID != client.ID {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+
+	// Checked before the code is spent, so a bad request cannot burn it.
+	if time.Now().After(code.ExpiresAt) ||
+		code.RedirectURI != r.PostForm.Get("redirect_uri") ||
+		!security.VerifyPKCE(r.PostForm.Get("code_verifier"), code.CodeChallenge) {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+
+	// A code presented twice may have been intercepted, so the tokens issued
+	// for it are revoked as RFC 6749 section 4.1.2 recommends.
+	used, err := o.codes.Use(r.Context(), code.ID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !used {
+		if err = o.tokens.RevokeCode(r.Context(), code.ID); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+
+	o.issue(w, r, models.OAuthToken{
+		ClientID: client.ID,
+		UserID:   code.UserID,
+		CodeID:   code.ID,
+		Scopes:   code.Scopes,
+	})
+}
+
+func (o *OAuth) refresh(w http.ResponseWriter, r *http.Request, client models.OAuthClient) {
+	refreshToken := r.PostForm.Get("refresh_token")
+	if refreshToken == "" {
+		oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", "refresh_token")
+		return
+	}
+
+	token, err := o.tokens.SearchRefreshToken(r.Context(), security.HashToken(refreshToken))
+	if errors.Is(err, domain.ErrNotFound) {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if token.ClientID != client.ID {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+
+	scopes := token.Scopes
+	if scope := r.PostForm.Get("scope"); scope != "" {
+		scopes = strings.Fields(scope)
+		if !(models.OAuthConsent{Scopes: token.Scopes}).Covers(scopes) {
+			oauthError(w, r, http.StatusBadRequest, "invalid_scope", "oauth.invalid_scope")
+			return
+		}
+	}
+
+	revoked, err := o.tokens.Revoke(r.Context(), token.ID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !revoked {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+
+	o.issue(w, r, models.OAuthToken{
+		ClientID: client.ID,
+		UserID:   token.UserID,
+		CodeID:   token.CodeID,
+		Scopes:   scopes,
+	})
+}
+
+func (o *OAuth) issue(w http.ResponseWriter, r *http.Request, token models.OAuthToken) {
+	user, err := o.users.SearchID(r.Context(), token.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "oauth.invalid_grant")
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		oauthError(w, r, http.StatusBadRequest, "invalid_grant", "user.suspended")
+		return
+	}
+
+	accessToken, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	refreshToken, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	accessToken = authentication.OAuthAccessTokenPrefix + accessToken
+	refreshToken = authentication.OAuthRefreshTokenPrefix + refreshToken
+
+	token.AccessTokenHash = security.HashToken(accessToken)
+	token.RefreshTokenHash = security.HashToken(refreshToken)
+	token.ExpiresAt = time.Now().Add(config.AccessTokenDuration)
+	token.RefreshExpiresAt = time.Now().Add(config.RefreshTokenDuration)
+
+	if _, err = o.tokens.Create(r.Context(), token); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	answers.JSON(w, http.StatusOK, models.Authentication{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(config.AccessTokenDuration.Seconds()),
+		Scope:        strings.Join(token.Scopes, " "),
+	})
+}
+
+// Introspect follows RFC 7662. Clients can only introspect their own tokens;
+// any other token is reported as inactive.
+func (o *OAuth) Introspect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", "body")
+		return
+	}
+
+	client, err := o.authenticateClient(r)
+	if err != nil {
+		o.clientError(w, r, err)
+		return
+	}
+
+	if r.PostForm.Get("token") == "" {
+		oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", "token")
+		return
+	}
+
+	token, tokenType, err := o.searchToken(r.Context(), r.PostForm.Get("token"))
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	introspection := models.OAuthIntrospection{}
+	if token.ID != 0 && token.ClientID == client.ID {
+		expiresAt := token.ExpiresAt
+		if tokenType == "refresh_token" {
+			expiresAt = token.RefreshExpiresAt
+		}
+
+		introspection = models.OAuthIntrospection{
+			Active:    true,
+			Scope:     strings.Join(token.Scopes, " "),
+			ClientID:  token.Client,
+			Subject:   strconv.FormatUint(token.UserID, 10),
+			TokenType: tokenType,
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  token.CreatedAt.Unix(),
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	answers.JSON(w, http.StatusOK, introspection)
+}
+
+// Revoke follows RFC 7009: unknown tokens are not an error, so the answer is
+// the same whether or not something was revoked.
+func (o *OAuth) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", "body")
+		return
+	}
+
+	client, err := o.authenticateClient(r)
+	if err != nil {
+		o.clientError(w, r, err)
+		return
+	}
+
+	if r.PostForm.Get("token") == "" {
+		oauthError(w, r, http.StatusBadRequest, "invalid_request", "oauth.invalid_request", "token")
+		return
+	}
+
+	token, _, err := o.searchToken(r.Context(), r.PostForm.Get("token"))
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if token.ID != 0 && token.ClientID == client.ID {
+		if _, err = o.tokens.Revoke(r.Context(), token.ID); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (o *OAuth) SearchConsents(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "oauth.forbidden_consent")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	consents, err := o.consents.SearchUser(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(consents, consentCursor))
+}
+
+// RevokeConsent also revokes every token the client holds for the user.
+func (o *OAuth) RevokeConsent(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "oauth.forbidden_consent")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	client, err := o.clients.SearchClientID(r.Context(), mux.Vars(r)["clientId"])
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, domain.NotFound("oauth.consent_not_found"))
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	deleted, err := o.consents.Delete(r.Context(), userID, client.ID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !deleted {
+		answers.Error(w, r, domain.NotFound("oauth.consent_not_found"))
+		return
+	}
+
+	if err = o.tokens.RevokeConsent(r.Context(), userID, client.ID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// validateAuthorization only accepts the authorization code flow with S256
+// PKCE challenges, for public and confidential clients alike.
+func (o *OAuth) validateAuthorization(ctx context.Context, authorization *models.OAuthAuthorization) (models.OAuthClient, error) {
+	client, err := o.clients.SearchClientID(ctx, authorization.ClientID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.OAuthClient{}, domain.Validation("oauth.invalid_client")
+	}
+	if err != nil {
+		return models.OAuthClient{}, err
+	}
+
+	if authorization.RedirectURI == "" && len(client.RedirectURIs) == 1 {
+		authorization.RedirectURI = client.RedirectURIs[0]
+	}
+
+	if !client.AllowsRedirect(authorization.RedirectURI) {
+		return models.OAuthClient{}, domain.Validation("oauth.redirect_uri_invalid")
+	}
+
+	if authorization.ResponseType != "code" {
+		return models.OAuthClient{}, domain.Validation("oauth.unsupported_response_type")
+	}
+
+	if authorization.CodeChallenge == "" || authorization.CodeChallengeMethod != "S256" {
+		return models.OAuthClient{}, domain.Validation("oauth.code_challenge_required")
+	}
+
+	authorization.Scopes = mergeScopes(nil, strings.Fields(authorization.Scope))
+	if len(authorization.Scopes) == 0 {
+		return models.OAuthClient{}, domain.Validation("validation.scopes_required")
+	}
+
+	for _, scope := range authorization.Scopes {
+		if !authentication.ValidScope(scope) {
+			return models.OAuthClient{}, domain.Validation("validation.scope_invalid", scope)
+		}
+	}
+
+	authorization.ClientName = client.Name
+	return client, nil
+}
+
+// authenticateClient accepts the credentials in the Authorization header or
+// in the form. Public clients only identify themselves with client_id.
+func (o *OAuth) authenticateClient(r *http.Request) (models.OAuthClient, error) {
+	clientID, secret, basic := r.BasicAuth()
+	if !basic {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	if clientID == "" {
+		return models.OAuthClient{}, errOAuthInvalidClient
+	}
+
+	client, err := o.clients.SearchClientID(r.Context(), clientID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return models.OAuthClient{}, errOAuthInvalidClient
+	}
+	if err != nil {
+		return models.OAuthClient{}, err
+	}
+
+	if client.Confidential &&
+		subtle.ConstantTimeCompare([]byte(security.HashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return models.OAuthClient{}, errOAuthInvalidClient
+	}
+
+	return client, nil
+}
+
+func (o *OAuth) clientError(w http.ResponseWriter, r *http.Request, err error) {
+	if !errors.Is(err, errOAuthInvalidClient) {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if _, _, basic := r.BasicAuth(); basic {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	}
+
+	oauthError(w, r, http.StatusUnauthorized, "invalid_client", "oauth.invalid_client")
+}
+
+// searchToken finds access and refresh tokens alike, answering an empty token
+// when neither matches.
+func (o *OAuth) searchToken(ctx context.Context, value string) (models.OAuthToken, string, error) {
+	hash := security.HashToken(value)
+
+	token, err := o.tokens.SearchAccessToken(ctx, hash)
+	if err == nil {
+		return token, "access_token", nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return models.OAuthToken{}, "", err
+	}
+
+	token, err = o.tokens.SearchRefreshToken(ctx, hash)
+	if err == nil {
+		return token, "refresh_token", nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return models.OAuthToken{}, "", err
+	}
+
+	return models.OAuthToken{}, "", nil
+}
+
+func oauthError(w http.ResponseWriter, r *http.Request, statusCode int, code, key string, args ...interface{}) {
+	w.Header().Set("Cache-Control", "no-store")
+	answers.JSON(w, statusCode, models.OAuthError{
+		Error:       code,
+		Description: i18n.Translate(i18n.FromRequest(r), key, args...),
+	})
+}
+
+func redirectURL(redirectURI string, values url.Values) string {
+	redirect, _ := url.Parse(redirectURI)
+	query := redirect.Query()
+	for key := range values {
+		query.Set(key, values.Get(key))
+	}
+
+	redirect.RawQuery = query.Encode()
+	return redirect.String()
+}
+
+func mergeScopes(granted, requested []string) []string {
+	merged := append([]string{}, granted...)
+	for _, scope := range requested {
+		if !(models.OAuthConsent{Scopes: merged}).Covers([]string{scope}) {
+			merged = append(merged, scope)
+		}
+	}
+
+	return merged
+}
+
+func clientCursor(item interface{}) pagination.Cursor {
+	client := item.(models.OAuthClient)
+	return pagination.Cursor{CreatedAt: client.CreatedAt, ID: client.ID}
+}
+
+func consentCursor(item interface{}) pagination.Cursor {
+	consent := item.(models.OAuthConsent)
+	return pagination.Cursor{CreatedAt: consent.CreatedAt, ID: consent.ID}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"api/src/domain"
+	"api/src/models"
+	"api/src/repositories"
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeOAuthClients struct {
+	repositories.OAuthClients
+
+	saved map[string]models.OAuthClient
+}
+
+func (f *fakeOAuthClients) SearchClientID(ctx context.Context, clientID string) (models.OAuthClient, error) {
+	client, ok := f.saved[clientID]
+	if !ok {
+		return models.OAuthClient{}, domain.NotFound("oauth.client_not_found")
+	}
+
+	return client, nil
+}
+
+func TestValidateAuthorization(t *testing.T) {
+	clients := &fakeOAuthClients{saved: map[string]models.OAuthClient{
+		"single": {ClientID: "single", Name: "Single", RedirectURIs: []string{"https://app.example.com/callback"}},
+		"several": {ClientID: "several", Name: "Several", RedirectURIs: []string{
+			"https://app.example.com/callback", "http://127.0.0.1:8080/callback",
+		}},
+	}}
+
+	valid := func(change func(authorization *models.OAuthAuthorization)) models.OAuthAuthorization {
+		authorization := models.OAuthAuthorization{
+			ResponseType:        "code",
+			ClientID:            "single",
+			RedirectURI:         "https://app.example.com/callback",
+			Scope:               "publications:read",
+			CodeChallenge:       "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+			CodeChallengeMethod: "S256",
+		}
+		if change != nil {
+			change(&authorization)
+		}
+		return authorization
+	}
+
+	tests := []struct {
+		name          string
+		authorization models.OAuthAuthorization
+		wantKey       string
+		wantRedirect  string
+	}{
+		{"valid", valid(nil), "", "https://app.example.com/callback"},
+		{"single redirect uri by default", valid(func(a *models.OAuthAuthorization) { a.RedirectURI = "" }), "", "https://app.example.com/callback"},
+		{"loopback redirect uri", valid(func(a *models.OAuthAuthorization) {
+			a.ClientID, a.RedirectURI = "several", "http://127.0.0.1:8080/callback"
+		}), "", "http://127.0.0.1:8080/callback"},
+		{"no default among several redirect uris", valid(func(a *models.OAuthAuthorization) {
+			a.ClientID, a.RedirectURI = "several", ""
+		}), "oauth.redirect_uri_invalid", ""},
+		{"unknown client", valid(func(a *models.OAuthAuthorization) { a.ClientID = "unknown" }), "oauth.invalid_client", ""},
+		{"unregistered redirect uri", valid(func(a *models.OAuthAuthorization) {
+			a.RedirectURI = "https://app.example.com/callback/other"
+		}), "oauth.redirect_uri_invalid", ""},
+		{"implicit flow", valid(func(a *models.OAuthAuthorization) { a.ResponseType = "token" }), "oauth.unsupported_response_type", ""},
+		{"missing challenge", valid(func(a *models.OAuthAuthorization) { a.CodeChallenge = "" }), "oauth.code_challenge_required", ""},
+		{"plain challenge", valid(func(a *models.OAuthAuthorization) { a.CodeChallengeMethod = "plain" }), "oauth.code_challenge_required", ""},
+		{"missing method", valid(func(a *models.OAuthAuthorization) { a.CodeChallengeMethod = "" }), "oauth.code_challenge_required", ""},
+		{"missing scope", valid(func(a *models.OAuthAuthorization) { a.Scope = " " }), "validation.scopes_required", ""},
+		{"unknown scope", valid(func(a *models.OAuthAuthorization) { a.Scope = "publications:read admin" }), "validation.scope_invalid", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			authorization := test.authorization
+			_, err := (&OAuth{clients: clients}).validateAuthorization(context.Background(), &authorization)
+
+			var domainError *domain.Error
+			switch {
+			case test.wantKey == "" && err != nil:
+				t.Fatalf("validateAuthorization = %v, want it accepted", err)
+			case test.wantKey != "" && (!errors.As(err, &domainError) || domainError.Key != test.wantKey):
+				t.Fatalf("validateAuthorization = %v, want %s", err, test.wantKey)
+			}
+
+			if test.wantKey == "" && authorization.RedirectURI != test.wantRedirect {
+				t.Errorf("redirect uri = %q, want %q", authorization.RedirectURI, test.wantRedirect)
+			}
+		})
+	}
+}
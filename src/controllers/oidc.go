@@ -0,0 +1,298 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/oidc"
+	"api/src/security"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	maxNickLength = 50
+	maxNameLength = 50
+	nickAttempts  = 5
+)
+
+// StartOIDC answers the provider URL the front end must send the browser to,
+// along with the state it must hand back to OIDCCallback.
+func (l *Login) StartOIDC(w http.ResponseWriter, r *http.Request) {
+	if l.provider == nil {
+		answers.Error(w, r, domain.NotFound("oidc.disabled"))
+		return
+	}
+
+	state, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	nonce, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	codeVerifier, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	login := models.OIDCLogin{
+		StateHash:    security.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(config.OIDCLoginDuration),
+		State:        state,
+	}
+
+	login.AuthorizationURL, err = l.provider.AuthorizationURL(r.Context(), state, nonce, security.PKCEChallenge(codeVerifier))
+	if err != nil {
+		log.Printf("\n could not reach the OIDC provider: %v", err)
+		answers.Error(w, r, domain.Unauthorized("oidc.unavailable"))
+		return
+	}
+
+	if login.ID, err = l.oidcLogins.Create(r.Context(), login); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, login)
+}
+
+// OIDCCallback finishes the sign-in started at StartOIDC and answers the same
+// tokens, or two-factor challenge, as Login.
+func (l *Login) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if l.provider == nil {
+		answers.Error(w, r, domain.NotFound("oidc.disabled"))
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var callback models.OIDCCallback
+	if err = json.Unmarshal(bodyRequest, &callback); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var violations []domain.FieldError
+	if callback.Code == "" {
+		violations = append(violations, domain.Field("code", "validation.code_required"))
+	}
+	if callback.State == "" {
+		violations = append(violations, domain.Field("state", "validation.state_required"))
+	}
+	if err = domain.InvalidFields(violations); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	login, err := l.oidcLogins.Consume(r.Context(), security.HashToken(callback.State))
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, domain.Unauthorized("oidc.invalid_state"))
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	identity, err := l.provider.Exchange(r.Context(), callback.Code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		log.Printf("\n could not complete the OIDC sign-in: %v", err)
+		answers.Error(w, r, domain.Unauthorized("oidc.invalid_login"))
+		return
+	}
+
+	user, err := l.linkIdentity(r.Context(), identity)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		answers.Error(w, r, errSuspended)
+		return
+	}
+
+	twoFactor, err := l.users.SearchTwoFactor(r.Context(), user.ID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if twoFactor.Enabled {
+		challenge, err := l.createChallenge(r.Context(), user.ID)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.JSON(w, http.StatusOK, challenge)
+		return
+	}
+
+	if err = l.record(r, user.ID, user.Email, true, "oidc"); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	authenticationData, err := l.createSession(r, user)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, authenticationData)
+}
+
+// linkIdentity finds the user behind a provider identity. Unknown identities
+// are linked to the account with the same email, which both sides must have
+// verified, or get a new account.
+func (l *Login) linkIdentity(ctx context.Context, identity oidc.Identity) (models.User, error) {
+	linked, err := l.identities.SearchSubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return l.users.SearchID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return models.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return models.User{}, domain.Forbidden("oidc.email_unverified")
+	}
+
+	var user models.User
+	existing, err := l.users.SearchEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if user, err = l.users.SearchID(ctx, existing.ID); err != nil {
+			return models.User{}, err
+		}
+
+		// Otherwise whoever registered the address first, and knows its
+		// password, would share the account with its real owner.
+		if !user.EmailVerified {
+			return models.User{}, domain.Conflict("oidc.account_unverified")
+		}
+	case errors.Is(err, domain.ErrNotFound):
+		if user, err = l.createOIDCUser(ctx, identity); err != nil {
+			return models.User{}, err
+		}
+	default:
+		return models.User{}, err
+	}
+
+	if _, err = l.identities.Create(ctx, models.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+	}); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// createOIDCUser registers the account with a random password, which can be
+// replaced through the password reset flow.
+func (l *Login) createOIDCUser(ctx context.Context, identity oidc.Identity) (models.User, error) {
+	password, err := security.GenerateToken()
+	if err != nil {
+		return models.User{}, err
+	}
+
+	passwordWithHash, err := security.HashPassword(password)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	base := nickBase(identity)
+	user := models.User{
+		Name:     truncate(strings.TrimSpace(identity.Name), maxNameLength),
+		Email:    identity.Email,
+		Password: string(passwordWithHash),
+	}
+	if user.Name == "" {
+		user.Name = base
+	}
+
+	for attempt := 0; attempt < nickAttempts; attempt++ {
+		user.Nick = base
+		if attempt > 0 {
+			suffix, err := rand.Int(rand.Reader, big.NewInt(10000))
+			if err != nil {
+				return models.User{}, err
+			}
+
+			user.Nick = fmt.Sprintf("%s%04d", truncate(base, maxNickLength-4), suffix.Int64())
+		}
+
+		user.ID, err = l.users.Create(ctx, user)
+		if errors.Is(err, domain.ErrConflict) {
+			continue
+		}
+		if err != nil {
+			return models.User{}, err
+		}
+
+		if err = l.users.VerifyEmail(ctx, user.ID, user.Email); err != nil {
+			return models.User{}, err
+		}
+
+		return l.users.SearchID(ctx, user.ID)
+	}
+
+	return models.User{}, err
+}
+
+// nickBase keeps the letters, digits, dots and underscores of the preferred
+// username, or of the email local part when the provider sends none.
+func nickBase(identity oidc.Identity) string {
+	source := identity.PreferredUsername
+	if source == "" {
+		source = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+
+	var nick strings.Builder
+	for _, character := range strings.ToLower(source) {
+		if character < unicode.MaxASCII && (unicode.IsLetter(character) || unicode.IsDigit(character) || character == '.' || character == '_') {
+			nick.WriteRune(character)
+		}
+	}
+
+	if nick.Len() == 0 {
+		return "user"
+	}
+
+	return truncate(nick.String(), maxNickLength)
+}
+
+func truncate(value string, length int) string {
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length])
+	}
+
+	return value
+}
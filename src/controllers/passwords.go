@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/i18n"
+	"api/src/mail"
+	"api/src/models"
+	"api/src/repositories"
+	"api/src/security"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Passwords struct {
+	users         repositories.Users
+	resets        repositories.PasswordResets
+	sessions      repositories.Sessions
+	tokens        repositories.PersonalAccessTokens
+	oauthTokens   repositories.OAuthTokens
+	oauthConsents repositories.OAuthConsents
+	mailer        mail.Mailer
+}
+
+func NewControllerPasswords(users repositories.Users, resets repositories.PasswordResets, sessions repositories.Sessions, tokens repositories.PersonalAccessTokens, oauthTokens repositories.OAuthTokens, oauthConsents repositories.OAuthConsents, mailer mail.Mailer) *Passwords {
+	return &Passwords{users, resets, sessions, tokens, oauthTokens, oauthConsents, mailer}
+}
+
+// ForgotPassword always answers 204, and rate limits unknown emails like
+// registered ones, so the endpoint cannot be used to find out which emails are
+// registered. The link goes to the address stored for the account.
+func (p *Passwords) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var user models.User
+	if err = json.Unmarshal(bodyRequest, &user); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	user.Email = truncate(strings.TrimSpace(user.Email), maxEmailLength)
+	if user.Email == "" {
+		answers.Error(w, r, domain.InvalidFields([]domain.FieldError{domain.Field("email", "validation.email_required")}))
+		return
+	}
+
+	since := time.Now().Add(-config.PasswordResetWindow)
+	requests, err := p.resets.SearchEmailRequests(r.Context(), user.Email, since)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = throttle(requests, config.PasswordResetMaxRequests, config.PasswordResetWindow, "password_reset.too_many"); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	ip := security.ClientIP(r)
+	if requests, err = p.resets.SearchIPRequests(r.Context(), ip, since); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = throttle(requests, config.PasswordResetMaxRequestsPerIP, config.PasswordResetWindow, "password_reset.too_many"); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	reset := models.PasswordReset{
+		Email:     user.Email,
+		IP:        ip,
+		ExpiresAt: time.Now().Add(config.PasswordResetDuration),
+	}
+
+	userSaveDatabase, err := p.users.SearchEmail(r.Context(), user.Email)
+	if errors.Is(err, domain.ErrNotFound) {
+		if _, err = p.resets.Create(r.Context(), reset); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.JSON(w, http.StatusNoContent, nil)
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = p.resets.RevokeUser(r.Context(), userSaveDatabase.ID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	token, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	reset.UserID = userSaveDatabase.ID
+	reset.TokenHash = security.HashToken(token)
+	if _, err = p.resets.Create(r.Context(), reset); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	locale := i18n.FromRequest(r)
+	deliver(p.mailer, mail.Message{
+		To:      userSaveDatabase.Email,
+		Subject: i18n.Translate(locale, "mail.password_reset.subject"),
+		Body: i18n.Translate(locale, "mail.password_reset.body",
+			fmt.Sprintf("%s/password/reset?token=%s", config.AppURL, url.QueryEscape(token)),
+			config.PasswordResetDuration,
+		),
+	})
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (p *Passwords) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var password models.Password
+	if err = json.Unmarshal(bodyRequest, &password); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var violations []domain.FieldError
+	if password.Token == "" {
+		violations = append(violations, domain.Field("token", "validation.token_required"))
+	}
+	if password.New == "" {
+		violations = append(violations, domain.Field("new", "validation.password_required"))
+	}
+	if err = domain.InvalidFields(violations); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	userID, err := p.resets.Consume(r.Context(), security.HashToken(password.Token))
+	if errors.Is(err, domain.ErrNotFound) {
+		answers.Error(w, r, domain.Validation("password_reset.invalid_token"))
+		return
+	}
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	passwordWithHash, err := security.HashPassword(password.New)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = p.users.UpdatePassword(r.Context(), userID, string(passwordWithHash)); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// Whoever forced the reset may hold any credential of the account.
+	if err = revokeCredentials(r.Context(), userID, p.sessions, p.tokens, p.oauthTokens, p.oauthConsents); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// revokeCredentials signs the user out everywhere: every session, personal
+// access token and authorized OAuth application goes.
+func revokeCredentials(ctx context.Context, userID uint64, sessions repositories.Sessions, tokens repositories.PersonalAccessTokens, oauthTokens repositories.OAuthTokens, oauthConsents repositories.OAuthConsents) error {
+	if err := sessions.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := tokens.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := oauthTokens.RevokeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return oauthConsents.DeleteUser(ctx, userID)
+}
@@ -3,11 +3,11 @@ package controllers
 import (
 	"api/src/answers"
 	"api/src/authentication"
-	"api/src/database"
+	"api/src/domain"
 	"api/src/models"
+	"api/src/pagination"
 	"api/src/repositories"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -15,266 +15,295 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func CreatePublication(w http.ResponseWriter, r *http.Request) {
+type Publications struct {
+	publications repositories.Publications
+}
+
+func NewControllerPublications(publications repositories.Publications) *Publications {
+	return &Publications{publications}
+}
+
+func (p *Publications) CreatePublication(w http.ResponseWriter, r *http.Request) {
 	userID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	bodyRequest, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		answers.Err(w, http.StatusUnprocessableEntity, err)
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	var publication models.Publication
 	if err := json.Unmarshal(bodyRequest, &publication); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	publication.AuthorID = userID
 
 	if err = publication.Prepare(); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
-		return
-	}
-
-	db, err := database.Connect()
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	publication.ID, err = repository.Create(publication)
+	publication.ID, err = p.publications.Create(r.Context(), publication)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusCreated, publication)
 }
 
-func SearchPublications(w http.ResponseWriter, r *http.Request) {
+func (p *Publications) SearchPublications(w http.ResponseWriter, r *http.Request) {
 	userID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	db, err := database.Connect()
+	params, err := pagination.FromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	publications, err := repository.Search(userID)
+	publications, err := p.publications.Search(r.Context(), userID, params)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	answers.JSON(w, http.StatusOK, publications)
+	answers.Page(w, r, params.Page(publications, publicationCursor))
 }
 
-func SearchPublication(w http.ResponseWriter, r *http.Request) {
-	parameters := mux.Vars(r)
-	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+func (p *Publications) SearchPublication(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	db, err := database.Connect()
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	publication, err := repository.SearchID(publicationID)
+	publication, err := p.publications.SearchID(r.Context(), publicationID, userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusOK, publication)
 }
 
-func UpdatePublication(w http.ResponseWriter, r *http.Request) {
+func (p *Publications) UpdatePublication(w http.ResponseWriter, r *http.Request) {
 	userID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	parameters := mux.Vars(r)
 	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
-		return
-	}
-
-	db, err := database.Connect()
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	publicationSavedDatabase, err := repository.SearchID(publicationID)
+	publicationSavedDatabase, err := p.publications.SearchID(r.Context(), publicationID, userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if userID != publicationSavedDatabase.AuthorID {
-		answers.Err(w, http.StatusForbidden, errors.New("you cannot change a post that is not yours"))
+		answers.Error(w, r, domain.Forbidden("publication.forbidden_update"))
 		return
 	}
 
 	bodyRequest, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		answers.Err(w, http.StatusUnprocessableEntity, err)
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	var publication models.Publication
 	if err := json.Unmarshal(bodyRequest, &publication); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	if err = publication.Prepare(); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	if err = repository.Update(publicationID, publication); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if err = p.publications.Update(r.Context(), publicationID, publication); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
 
-func DeletePublication(w http.ResponseWriter, r *http.Request) {
+func (p *Publications) DeletePublication(w http.ResponseWriter, r *http.Request) {
 	userID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	parameters := mux.Vars(r)
 	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
-		return
-	}
-
-	db, err := database.Connect()
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	publicationSavedDatabase, err := repository.SearchID(publicationID)
+	publicationSavedDatabase, err := p.publications.SearchID(r.Context(), publicationID, userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if userID != publicationSavedDatabase.AuthorID {
-		answers.Err(w, http.StatusForbidden, errors.New("you cannot delete a post that is not yours"))
+		answers.Error(w, r, domain.Forbidden("publication.forbidden_delete"))
 		return
 	}
 
-	if err = repository.Delete(publicationID); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if err = p.publications.Delete(r.Context(), publicationID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
 
-func SearchPublicationsUser(w http.ResponseWriter, r *http.Request) {
+func (p *Publications) SearchPublicationsUser(w http.ResponseWriter, r *http.Request) {
+	viewerID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	db, err := database.Connect()
+	params, err := pagination.FromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	publications, err := repository.SearchUser(userID)
+	publications, err := p.publications.SearchUser(r.Context(), userID, viewerID, params)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	answers.JSON(w, http.StatusOK, publications)
+	answers.Page(w, r, params.Page(publications, publicationCursor))
 }
 
-func LikePublication(w http.ResponseWriter, r *http.Request) {
+func (p *Publications) LikePublication(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
 	parameters := mux.Vars(r)
 	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	db, err := database.Connect()
+	if _, err = p.publications.SearchID(r.Context(), publicationID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = p.publications.Like(r.Context(), publicationID, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (p *Publications) UnlikePublication(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	if err = repository.LikePublication(publicationID); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if err = p.publications.Unlike(r.Context(), publicationID, userID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
 
-func DislikePublication(w http.ResponseWriter, r *http.Request) {
+func (p *Publications) SearchLikes(w http.ResponseWriter, r *http.Request) {
+	userID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
 	parameters := mux.Vars(r)
 	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	db, err := database.Connect()
+	params, err := pagination.FromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryPublications(db)
-	if err = repository.DislikePublication(publicationID); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if _, err = p.publications.SearchID(r.Context(), publicationID, userID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
-	answers.JSON(w, http.StatusNoContent, nil)
+	likes, err := p.publications.SearchLikes(r.Context(), publicationID, userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(likes, likeCursor))
+}
+
+func likeCursor(item interface{}) pagination.Cursor {
+	like := item.(models.Like)
+	return pagination.Cursor{CreatedAt: like.CreatedAt, ID: like.UserID}
+}
+
+func publicationCursor(item interface{}) pagination.Cursor {
+	publication := item.(models.Publication)
+	return pagination.Cursor{CreatedAt: publication.CreatedAt, ID: publication.ID}
 }
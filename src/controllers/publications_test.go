@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/repositories"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// fakePublications keeps publications in memory. Methods the tests do not
+// exercise fall through to the nil embedded interface and panic.
+type fakePublications struct {
+	repositories.Publications
+
+	saved   map[uint64]models.Publication
+	created []models.Publication
+	updated []uint64
+	deleted []uint64
+}
+
+func (f *fakePublications) Create(ctx context.Context, publication models.Publication) (uint64, error) {
+	f.created = append(f.created, publication)
+	return uint64(len(f.created)), nil
+}
+
+func (f *fakePublications) SearchID(ctx context.Context, publicationID, viewerID uint64) (models.Publication, error) {
+	publication, ok := f.saved[publicationID]
+	if !ok {
+		return models.Publication{}, domain.NotFound("publication.not_found")
+	}
+
+	return publication, nil
+}
+
+func (f *fakePublications) Update(ctx context.Context, publicationID uint64, publication models.Publication) error {
+	f.updated = append(f.updated, publicationID)
+	return nil
+}
+
+func (f *fakePublications) Delete(ctx context.Context, publicationID uint64) error {
+	f.deleted = append(f.deleted, publicationID)
+	return nil
+}
+
+func newPublicationRequest(method, body string, userID uint64, vars map[string]string) *http.Request {
+	r := httptest.NewRequest(method, "/publications", strings.NewReader(body))
+	if userID != 0 {
+		r = r.WithContext(authentication.NewContext(r.Context(), authentication.Principal{UserID: userID}))
+	}
+
+	return mux.SetURLVars(r, vars)
+}
+
+func TestCreatePublication(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     uint64
+		body       string
+		wantStatus int
+		wantSaved  bool
+	}{
+		{"anonymous", 0, `{"title":"t","content":"c"}`, http.StatusUnauthorized, false},
+		{"malformed body", 1, `{"title":`, http.StatusBadRequest, false},
+		{"missing content", 1, `{"title":"t"}`, http.StatusBadRequest, false},
+		{"created", 1, `{"title":" t ","content":"c","authorid":9}`, http.StatusCreated, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publications := &fakePublications{}
+			w := httptest.NewRecorder()
+
+			NewControllerPublications(publications).CreatePublication(w, newPublicationRequest(http.MethodPost, test.body, test.userID, nil))
+
+			if w.Code != test.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, test.wantStatus, w.Body)
+			}
+
+			if saved := len(publications.created) == 1; saved != test.wantSaved {
+				t.Fatalf("saved = %v, want %v", saved, test.wantSaved)
+			}
+
+			if !test.wantSaved {
+				return
+			}
+
+			// The author always comes from the principal, never from the body.
+			created := publications.created[0]
+			if created.AuthorID != test.userID || created.Title != "t" {
+				t.Errorf("created %+v, want a trimmed title authored by user %d", created, test.userID)
+			}
+
+			var answered models.Publication
+			if err := json.Unmarshal(w.Body.Bytes(), &answered); err != nil || answered.ID != 1 {
+				t.Errorf("answered %s, want the publication with its new id", w.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateAndDeletePublication(t *testing.T) {
+	saved := map[uint64]models.Publication{
+		1: {ID: 1, Title: "t", Content: "c", AuthorID: 10},
+	}
+
+	tests := []struct {
+		name          string
+		userID        uint64
+		publicationID string
+		wantStatus    int
+	}{
+		{"author", 10, "1", http.StatusNoContent},
+		{"another user", 11, "1", http.StatusForbidden},
+		{"unknown publication", 10, "2", http.StatusNotFound},
+		{"invalid id", 10, "one", http.StatusBadRequest},
+		{"anonymous", 0, "1", http.StatusUnauthorized},
+	}
+
+	handlers := []struct {
+		method  string
+		handler func(p *Publications) http.HandlerFunc
+		changed func(f *fakePublications) []uint64
+	}{
+		{
+			http.MethodPut,
+			func(p *Publications) http.HandlerFunc { return p.UpdatePublication },
+			func(f *fakePublications) []uint64 { return f.updated },
+		},
+		{
+			http.MethodDelete,
+			func(p *Publications) http.HandlerFunc { return p.DeletePublication },
+			func(f *fakePublications) []uint64 { return f.deleted },
+		},
+	}
+
+	for _, handler := range handlers {
+		for _, test := range tests {
+			t.Run(handler.method+" "+test.name, func(t *testing.T) {
+				publications := &fakePublications{saved: saved}
+				w := httptest.NewRecorder()
+				r := newPublicationRequest(handler.method, `{"title":"new","content":"new"}`, test.userID,
+					map[string]string{"publicationId": test.publicationID})
+
+				handler.handler(NewControllerPublications(publications))(w, r)
+
+				if w.Code != test.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", w.Code, test.wantStatus, w.Body)
+				}
+
+				changed := handler.changed(publications)
+				if wantChanged := test.wantStatus == http.StatusNoContent; (len(changed) == 1) != wantChanged {
+					t.Errorf("changed publications %v, want a change only for the author", changed)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"api/src/repositories"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type Reports struct {
+	reports      repositories.Reports
+	users        repositories.Users
+	publications repositories.Publications
+}
+
+func NewControllerReports(reports repositories.Reports, users repositories.Users, publications repositories.Publications) *Reports {
+	return &Reports{reports, users, publications}
+}
+
+func (rp *Reports) ReportPublication(w http.ResponseWriter, r *http.Request) {
+	parameters := mux.Vars(r)
+	publicationID, err := strconv.ParseUint(parameters["publicationId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	reporterID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	publication, err := rp.publications.SearchID(r.Context(), publicationID, reporterID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	rp.create(w, r, models.Report{
+		Kind:               models.ReportPublication,
+		ReporterID:         reporterID,
+		UserID:             publication.AuthorID,
+		PublicationID:      publication.ID,
+		UserNick:           publication.AuthorNick,
+		PublicationTitle:   publication.Title,
+		PublicationContent: publication.Content,
+	})
+}
+
+// ReportUser accepts reports on users that blocked the reporter, or were
+// blocked by them, as blocking often follows the abuse being reported.
+func (rp *Reports) ReportUser(w http.ResponseWriter, r *http.Request) {
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	reporterID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	user, err := rp.users.SearchID(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	rp.create(w, r, models.Report{
+		Kind:       models.ReportUser,
+		ReporterID: reporterID,
+		UserID:     userID,
+		UserNick:   user.Nick,
+	})
+}
+
+// create fills the reason and details of the target report from the body.
+func (rp *Reports) create(w http.ResponseWriter, r *http.Request, target models.Report) {
+	if target.UserID == target.ReporterID {
+		answers.Error(w, r, domain.Forbidden("report.self"))
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var report models.Report
+	if err = json.Unmarshal(bodyRequest, &report); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = report.Prepare(); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	target.Reason = report.Reason
+	target.Details = report.Details
+	if target.ID, err = rp.reports.Create(r.Context(), target); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusCreated, models.Report{ID: target.ID, Kind: target.Kind, Reason: target.Reason, Status: models.ReportOpen})
+}
+
+// SearchReports lists the moderation queue, the open reports unless another
+// status is asked for.
+func (rp *Reports) SearchReports(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = models.ReportOpen
+	}
+	if !models.ValidReportStatus(status) {
+		answers.Error(w, r, domain.Validation("request.invalid_parameter", "status"))
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// The oldest reports have waited the longest, so the queue starts with them.
+	params.Ascending = status == models.ReportOpen
+
+	reports, err := rp.reports.Search(r.Context(), status, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(reports, reportCursor))
+}
+
+func (rp *Reports) SearchReport(w http.ResponseWriter, r *http.Request) {
+	parameters := mux.Vars(r)
+	reportID, err := strconv.ParseUint(parameters["reportId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := rp.reports.SearchID(r.Context(), reportID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if report.Actions, err = rp.reports.SearchActions(r.Context(), reportID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, report)
+}
+
+// DecideReport closes the report, taking the action on the reported content or
+// its author and recording the moderator who decided it.
+func (rp *Reports) DecideReport(w http.ResponseWriter, r *http.Request) {
+	principal, err := authentication.PrincipalFromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	reportID, err := strconv.ParseUint(parameters["reportId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var action models.ReportAction
+	if err = json.Unmarshal(bodyRequest, &action); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := rp.reports.SearchID(r.Context(), reportID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if report.Status != models.ReportOpen {
+		answers.Error(w, r, domain.Conflict("report.closed"))
+		return
+	}
+
+	if err = action.Prepare(report.Kind); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = rp.authorizeAction(r, principal, report, action.Action); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	action.ModeratorID = principal.UserID
+	decided, err := rp.reports.Decide(r.Context(), report, action)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !decided {
+		answers.Error(w, r, domain.Conflict("report.closed"))
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+// authorizeAction checks the moderator may take the action on the report
+// target, which must still exist.
+func (rp *Reports) authorizeAction(r *http.Request, principal authentication.Principal, report models.Report, action string) error {
+	switch action {
+	case models.ActionHide, models.ActionDelete:
+		if !principal.Can(authentication.PermissionDeleteAnyPublication) {
+			return domain.Forbidden("authentication.forbidden")
+		}
+
+		if report.PublicationID == 0 {
+			return domain.NotFound("publication.not_found")
+		}
+	case models.ActionSuspend:
+		if !principal.Can(authentication.PermissionSuspendAccounts) {
+			return domain.Forbidden("authentication.forbidden")
+		}
+
+		user, err := rp.users.SearchID(r.Context(), report.UserID)
+		if err != nil {
+			return err
+		}
+
+		return canSuspend(principal, user)
+	}
+
+	return nil
+}
+
+func reportCursor(item interface{}) pagination.Cursor {
+	report := item.(models.Report)
+	return pagination.Cursor{CreatedAt: report.CreatedAt, ID: report.ID}
+}
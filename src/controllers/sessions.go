@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"api/src/repositories"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type Sessions struct {
+	sessions repositories.Sessions
+}
+
+func NewControllerSessions(sessions repositories.Sessions) *Sessions {
+	return &Sessions{sessions}
+}
+
+func (s *Sessions) SearchSessions(w http.ResponseWriter, r *http.Request) {
+	principal, err := authentication.PrincipalFromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if userID != principal.UserID {
+		answers.Error(w, r, domain.Forbidden("session.forbidden"))
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	sessions, err := s.sessions.SearchUser(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == principal.SessionID
+	}
+
+	answers.Page(w, r, params.Page(sessions, sessionCursor))
+}
+
+func (s *Sessions) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	principal, err := authentication.PrincipalFromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(parameters["sessionId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if userID != principal.UserID {
+		answers.Error(w, r, domain.Forbidden("session.forbidden"))
+		return
+	}
+
+	revoked, err := s.sessions.RevokeUserSession(r.Context(), sessionID, userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !revoked {
+		answers.Error(w, r, domain.NotFound("session.not_found"))
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func sessionCursor(item interface{}) pagination.Cursor {
+	session := item.(models.Session)
+	return pagination.Cursor{CreatedAt: session.CreatedAt, ID: session.ID}
+}
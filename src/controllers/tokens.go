@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/authentication"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"api/src/repositories"
+	"api/src/security"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type Tokens struct {
+	tokens repositories.PersonalAccessTokens
+}
+
+func NewControllerTokens(tokens repositories.PersonalAccessTokens) *Tokens {
+	return &Tokens{tokens}
+}
+
+// CreateToken answers the only copy of the token, which is stored hashed.
+func (t *Tokens) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "token.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var token models.PersonalAccessToken
+	if err = json.Unmarshal(bodyRequest, &token); err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = token.Prepare(authentication.ValidScope); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	secret, err := security.GenerateToken()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	token.UserID = userID
+	token.Token = authentication.PersonalAccessTokenPrefix + secret
+	token.TokenHash = security.HashToken(token.Token)
+
+	token.ID, err = t.tokens.Create(r.Context(), token)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusCreated, token)
+}
+
+func (t *Tokens) SearchTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "token.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	tokens, err := t.tokens.SearchUser(r.Context(), userID, params)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.Page(w, r, params.Page(tokens, tokenCursor))
+}
+
+func (t *Tokens) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "token.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	parameters := mux.Vars(r)
+	tokenID, err := strconv.ParseUint(parameters["tokenId"], 10, 64)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	revoked, err := t.tokens.Revoke(r.Context(), tokenID, userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !revoked {
+		answers.Error(w, r, domain.NotFound("token.not_found"))
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func tokenCursor(item interface{}) pagination.Cursor {
+	token := item.(models.PersonalAccessToken)
+	return pagination.Cursor{CreatedAt: token.CreatedAt, ID: token.ID}
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"api/src/answers"
+	"api/src/config"
+	"api/src/domain"
+	"api/src/models"
+	"api/src/repositories"
+	"api/src/security"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const recoveryCodesCount = 10
+
+var errInvalidTwoFactorCode = domain.Unauthorized("two_factor.invalid_code")
+
+type TwoFactor struct {
+	users         repositories.Users
+	recoveryCodes repositories.RecoveryCodes
+}
+
+func NewControllerTwoFactor(users repositories.Users, recoveryCodes repositories.RecoveryCodes) *TwoFactor {
+	return &TwoFactor{users, recoveryCodes}
+}
+
+// EnrollTwoFactor stores a new secret that only takes effect after ConfirmTwoFactor.
+func (t *TwoFactor) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "two_factor.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	twoFactor, err := t.users.SearchTwoFactor(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if twoFactor.Enabled {
+		answers.Error(w, r, domain.Conflict("two_factor.already_enabled"))
+		return
+	}
+
+	user, err := t.users.SearchID(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = t.users.UpdateTwoFactor(r.Context(), userID, secret, false); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, models.TwoFactor{
+		Secret: secret,
+		URI:    security.TOTPProvisioningURI(config.TwoFactorIssuer, user.Email, secret),
+	})
+}
+
+func (t *TwoFactor) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := authorizeOwner(r, "two_factor.forbidden")
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	code, err := readTwoFactorCode(r)
+	if err != nil {
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if code.Code == "" {
+		answers.Error(w, r, domain.InvalidFields([]domain.FieldError{domain.Field("code", "validation.code_required")}))
+		return
+	}
+
+	twoFactor, err := t.users.SearchTwoFactor(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if twoFactor.Enabled {
+		answers.Error(w, r, domain.Conflict("two_factor.already_enabled"))
+		return
+	}
+
+	if twoFactor.Secret == "" {
+		answers.Error(w, r, domain.Validation("two_factor.not_enrolled"))
+		return
+	}
+
+	valid, err := verifyTOTP(r.Context(), t.users, userID, twoFactor.Secret, code.Code)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if !valid {
+		answers.Error(w, r, errInvalidTwoFactorCode)
+		return
+	}
+
+	if err = t.users.UpdateTwoFactor(r.Context(), userID, twoFactor.Secret, true); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	recoveryCodes, err := t.replaceRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, recoveryCodes)
+}
+
+func (t *TwoFactor) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := t.reauthenticate(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = t.users.UpdateTwoFactor(r.Context(), userID, "", false); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	if err = t.recoveryCodes.Replace(r.Context(), userID, nil); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusNoContent, nil)
+}
+
+func (t *TwoFactor) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, err := t.reauthenticate(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	recoveryCodes, err := t.replaceRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	answers.JSON(w, http.StatusOK, recoveryCodes)
+}
+
+// reauthenticate asks for the password again before changes that weaken or
+// reissue the second factor, and requires the second factor to be enabled.
+func (t *TwoFactor) reauthenticate(r *http.Request) (uint64, error) {
+	userID, err := authorizeOwner(r, "two_factor.forbidden")
+	if err != nil {
+		return 0, err
+	}
+
+	code, err := readTwoFactorCode(r)
+	if err != nil {
+		return 0, domain.Validation("request.invalid_body")
+	}
+
+	if code.Password == "" {
+		return 0, domain.InvalidFields([]domain.FieldError{domain.Field("password", "validation.password_required")})
+	}
+
+	passwordSavedDatabase, err := t.users.SearchPassword(r.Context(), userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = security.PasswordCheck(passwordSavedDatabase, code.Password); err != nil {
+		return 0, domain.Unauthorized("password.incorrect")
+	}
+
+	twoFactor, err := t.users.SearchTwoFactor(r.Context(), userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !twoFactor.Enabled {
+		return 0, domain.Validation("two_factor.not_enabled")
+	}
+
+	return userID, nil
+}
+
+func (t *TwoFactor) replaceRecoveryCodes(ctx context.Context, userID uint64) (models.RecoveryCodes, error) {
+	codes, err := security.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return models.RecoveryCodes{}, err
+	}
+
+	codeHashes := make([]string, len(codes))
+	for i, code := range codes {
+		codeHashes[i] = security.HashRecoveryCode(code)
+	}
+
+	if err = t.recoveryCodes.Replace(ctx, userID, codeHashes); err != nil {
+		return models.RecoveryCodes{}, err
+	}
+
+	return models.RecoveryCodes{Codes: codes}, nil
+}
+
+func readTwoFactorCode(r *http.Request) (models.TwoFactorCode, error) {
+	bodyRequest, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return models.TwoFactorCode{}, err
+	}
+
+	var code models.TwoFactorCode
+	if err = json.Unmarshal(bodyRequest, &code); err != nil {
+		return models.TwoFactorCode{}, err
+	}
+
+	return code, nil
+}
+
+func verifyTOTP(ctx context.Context, users repositories.Users, userID uint64, secret, code string) (bool, error) {
+	step, valid := security.ValidateTOTP(secret, code, time.Now())
+	if !valid {
+		return false, nil
+	}
+
+	return users.UseTOTPStep(ctx, userID, step)
+}
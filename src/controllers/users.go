@@ -3,13 +3,16 @@ package controllers
 import (
 	"api/src/answers"
 	"api/src/authentication"
-	"api/src/database"
+	"api/src/domain"
+	"api/src/i18n"
+	"api/src/mail"
 	"api/src/models"
+	"api/src/pagination"
 	"api/src/repositories"
 	"api/src/security"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,349 +20,442 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func CreateUser(w http.ResponseWriter, r *http.Request) {
+type Users struct {
+	users         repositories.Users
+	sessions      repositories.Sessions
+	tokens        repositories.PersonalAccessTokens
+	oauthTokens   repositories.OAuthTokens
+	oauthConsents repositories.OAuthConsents
+	verifications repositories.EmailVerifications
+	blocks        repositories.Blocks
+	mutes         repositories.Mutes
+	mailer        mail.Mailer
+}
+
+func NewControllerUsers(users repositories.Users, sessions repositories.Sessions, tokens repositories.PersonalAccessTokens, oauthTokens repositories.OAuthTokens, oauthConsents repositories.OAuthConsents, verifications repositories.EmailVerifications, blocks repositories.Blocks, mutes repositories.Mutes, mailer mail.Mailer) *Users {
+	return &Users{users, sessions, tokens, oauthTokens, oauthConsents, verifications, blocks, mutes, mailer}
+}
+
+func (u *Users) CreateUser(w http.ResponseWriter, r *http.Request) {
 	bodyRequest, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		answers.Err(w, http.StatusUnprocessableEntity, err)
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	var user models.User
 	if err = json.Unmarshal(bodyRequest, &user); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	if err = user.Prepare("register"); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	db, err := database.Connect()
+	user.ID, err = u.users.Create(r.Context(), user)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	user.ID, err = repository.Create(user)
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
-		return
+	// The account already exists and the link can be requested again, so a
+	// failure here must not make the client retry the registration.
+	if err = sendVerification(r.Context(), i18n.FromRequest(r), u.verifications, u.mailer, user.ID, user.Email); err != nil {
+		log.Printf("\n could not send the verification of user %d: %v", user.ID, err)
 	}
 
 	answers.JSON(w, http.StatusCreated, user)
 }
 
-func SearchUsers(w http.ResponseWriter, r *http.Request) {
+func (u *Users) SearchUsers(w http.ResponseWriter, r *http.Request) {
 	nameOuNick := strings.ToLower(r.URL.Query().Get("user"))
 
-	db, err := database.Connect()
+	params, err := pagination.FromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	users, err := repository.Search(nameOuNick)
+	viewerID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
 
+	users, err := u.users.Search(r.Context(), nameOuNick, viewerID, params)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	answers.JSON(w, http.StatusOK, users)
+	answers.Page(w, r, params.Page(models.Profiles(users), profileCursor))
 }
 
-func SearchUser(w http.ResponseWriter, r *http.Request) {
+func (u *Users) SearchUser(w http.ResponseWriter, r *http.Request) {
 	parameters := mux.Vars(r)
 
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = u.hideBlocked(r, userID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
-	db, err := database.Connect()
+	viewerID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	user, err := repository.SearchID(userID)
+	user, err := u.users.SearchID(r.Context(), userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
+		return
+	}
+
+	if viewerID != userID {
+		answers.JSON(w, http.StatusOK, user.Profile())
 		return
 	}
 
 	answers.JSON(w, http.StatusOK, user)
 }
 
-func UpdateUser(w http.ResponseWriter, r *http.Request) {
+func (u *Users) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	userIDToken, err := authentication.ExtractUserID(r)
+	principal, err := authentication.PrincipalFromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	if userID != userIDToken {
-		answers.Err(w, http.StatusForbidden, errors.New("you cannot change a user other than yours"))
+	if userID != principal.UserID {
+		answers.Error(w, r, domain.Forbidden("user.forbidden_update"))
 		return
 	}
 
 	bodyRequest, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		answers.Err(w, http.StatusUnprocessableEntity, err)
+		answers.Err(w, r, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	var user models.User
 	if err = json.Unmarshal(bodyRequest, &user); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	if err = user.Prepare("edit"); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	db, err := database.Connect()
+	userSavedDatabase, err := u.users.SearchID(r.Context(), userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
+		return
+	}
+
+	// With the email, whoever holds a token could reset the password and take
+	// the account over, so changing it takes a signed-in session.
+	if user.Email != userSavedDatabase.Email && principal.Restricted {
+		answers.Error(w, r, domain.Forbidden("user.email_change_restricted"))
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	if err = repository.Update(userID, user); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if err = u.users.Update(r.Context(), userID, user); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
+	if user.Email != userSavedDatabase.Email {
+		if err = sendVerification(r.Context(), i18n.FromRequest(r), u.verifications, u.mailer, userID, user.Email); err != nil {
+			log.Printf("\n could not send the verification of user %d: %v", userID, err)
+		}
+	}
+
 	answers.JSON(w, http.StatusNoContent, nil)
 
 }
 
-func DeleteUser(w http.ResponseWriter, r *http.Request) {
+func (u *Users) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	userIDToken, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if userID != userIDToken {
-		answers.Err(w, http.StatusForbidden, errors.New("you cannot delete a user other than yours"))
-		return
-	}
-
-	db, err := database.Connect()
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, domain.Forbidden("user.forbidden_delete"))
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	if err = repository.Delete(userID); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if err = u.users.Delete(r.Context(), userID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
 
-func FollowUser(w http.ResponseWriter, r *http.Request) {
+func (u *Users) FollowUser(w http.ResponseWriter, r *http.Request) {
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	followerID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if userID == followerID {
-		answers.Err(w, http.StatusForbidden, errors.New("you cannot follow your own username"))
+		answers.Error(w, r, domain.Forbidden("user.follow_self"))
 		return
 	}
 
-	db, err := database.Connect()
+	if err = u.hideBlocked(r, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	user, err := u.users.SearchID(r.Context(), userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	if err = repository.Follow(userID, followerID); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	following, err := u.users.IsFollowing(r.Context(), userID, followerID)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	// Private accounts approve their followers, so the follow waits as a request.
+	if user.Private && !following {
+		if err = u.users.RequestFollow(r.Context(), userID, followerID); err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		answers.JSON(w, http.StatusAccepted, nil)
+		return
+	}
+
+	if err = u.users.Follow(r.Context(), userID, followerID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
 
-func UnfollowollowUser(w http.ResponseWriter, r *http.Request) {
+func (u *Users) UnfollowollowUser(w http.ResponseWriter, r *http.Request) {
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	followerID, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if userID == followerID {
-		answers.Err(w, http.StatusForbidden, errors.New("you can't stop following your own username"))
+		answers.Error(w, r, domain.Forbidden("user.unfollow_self"))
 		return
 	}
 
-	db, err := database.Connect()
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if err = u.users.Unfollowollow(r.Context(), userID, followerID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	if err = repository.Unfollowollow(userID, followerID); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	if _, err = u.users.DeleteFollowRequest(r.Context(), userID, followerID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
 
-func SearchFollowers(w http.ResponseWriter, r *http.Request) {
+func (u *Users) SearchFollowers(w http.ResponseWriter, r *http.Request) {
+	viewerID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = u.hideBlocked(r, userID); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
-	db, err := database.Connect()
+	params, err := pagination.FromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	followers, err := repository.SearchFollowers(userID)
+	followers, err := u.users.SearchFollowers(r.Context(), userID, viewerID, params)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	answers.JSON(w, http.StatusOK, followers)
+	answers.Page(w, r, params.Page(models.Profiles(followers), profileCursor))
 }
 
-func SearchFollowing(w http.ResponseWriter, r *http.Request) {
+func (u *Users) SearchFollowing(w http.ResponseWriter, r *http.Request) {
+	viewerID, err := authentication.ExtractUserID(r)
+	if err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	db, err := database.Connect()
+	if err = u.hideBlocked(r, userID); err != nil {
+		answers.Error(w, r, err)
+		return
+	}
+
+	params, err := pagination.FromRequest(r)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
-	defer db.Close()
 
-	repository := repositories.NewRepositoryUsers(db)
-	following, err := repository.SearchFollowing(userID)
+	following, err := u.users.SearchFollowing(r.Context(), userID, viewerID, params)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 
-	answers.JSON(w, http.StatusOK, following)
+	answers.Page(w, r, params.Page(models.Profiles(following), profileCursor))
 }
 
-func UpdatePassword(w http.ResponseWriter, r *http.Request) {
+func (u *Users) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	parameters := mux.Vars(r)
 	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	userIDToken, err := authentication.ExtractUserID(r)
 	if err != nil {
-		answers.Err(w, http.StatusUnauthorized, err)
+		answers.Error(w, r, err)
 		return
 	}
 
 	if userID != userIDToken {
-		answers.Err(w, http.StatusForbidden, errors.New("you are not allowed to update the password of a user other than yours"))
+		answers.Error(w, r, domain.Forbidden("user.forbidden_password"))
 		return
 	}
 
 	bodyRequest, err := ioutil.ReadAll(r.Body)
 	var password models.Password
 	if err := json.Unmarshal(bodyRequest, &password); err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	db, err := database.Connect()
+	passwordSavedDatabase, err := u.users.SearchPassword(r.Context(), userID)
 	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
-		return
-	}
-	defer db.Close()
-
-	repository := repositories.NewRepositoryUsers(db)
-	passwordSavedDatabase, err := repository.SearchPassword(userID)
-	if err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+		answers.Error(w, r, err)
 		return
 	}
 	if err = security.PasswordCheck(passwordSavedDatabase, password.Current); err != nil {
-		answers.Err(w, http.StatusUnauthorized, errors.New("the new password does not match the current password"))
+		answers.Error(w, r, domain.Unauthorized("password.incorrect"))
 		return
 	}
 	passwordWithHash, err := security.HashPassword(password.New)
 	if err != nil {
-		answers.Err(w, http.StatusBadRequest, err)
+		answers.Err(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = u.users.UpdatePassword(r.Context(), userID, string(passwordWithHash)); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
-	if err = repository.UpdatePassword(userID, string(passwordWithHash)); err != nil {
-		answers.Err(w, http.StatusInternalServerError, err)
+	// The password may be changing because the account was compromised, so
+	// whatever the attacker could have created goes as well.
+	if err = revokeCredentials(r.Context(), userID, u.sessions, u.tokens, u.oauthTokens, u.oauthConsents); err != nil {
+		answers.Error(w, r, err)
 		return
 	}
 
 	answers.JSON(w, http.StatusNoContent, nil)
 }
+
+// authorizeOwner reads the userId route parameter and only accepts it when it
+// is the authenticated user, answering forbiddenKey otherwise.
+func authorizeOwner(r *http.Request, forbiddenKey string) (uint64, error) {
+	parameters := mux.Vars(r)
+	userID, err := strconv.ParseUint(parameters["userId"], 10, 64)
+	if err != nil {
+		return 0, domain.Validation("request.invalid_parameter", "userId")
+	}
+
+	userIDToken, err := authentication.ExtractUserID(r)
+	if err != nil {
+		return 0, err
+	}
+
+	if userID != userIDToken {
+		return 0, domain.Forbidden(forbiddenKey)
+	}
+
+	return userID, nil
+}
+
+func profileCursor(item interface{}) pagination.Cursor {
+	profile := item.(models.Profile)
+	return pagination.Cursor{CreatedAt: profile.CreatedAt, ID: profile.ID}
+}
+
+func userCursor(item interface{}) pagination.Cursor {
+	user := item.(models.User)
+	return pagination.Cursor{CreatedAt: user.CreatedAt, ID: user.ID}
+}
@@ -13,6 +13,10 @@ func Connect() (*sql.DB, error) {
 		return nil, err
 	}
 
+	db.SetMaxOpenConns(config.DatabaseMaxOpenConnections)
+	db.SetMaxIdleConns(config.DatabaseMaxIdleConnections)
+	db.SetConnMaxLifetime(config.DatabaseConnectionMaxLifetime)
+
 	if err = db.Ping(); err != nil {
 		db.Close()
 		return nil, err
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"api/src/i18n"
+	"errors"
+	"time"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindTooManyRequests
+)
+
+// Error carries a message key from the i18n catalog instead of the final text,
+// so answers can render it in the language negotiated with the client.
+type Error struct {
+	Kind   Kind
+	Key    string
+	Args   []interface{}
+	Fields []FieldError
+	Err    error
+
+	// RetryAfter tells the client how long to wait before trying again.
+	RetryAfter time.Duration
+}
+
+type FieldError struct {
+	Field   string        `json:"field"`
+	Key     string        `json:"code"`
+	Args    []interface{} `json:"-"`
+	Message string        `json:"message"`
+}
+
+var (
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrConflict     = &Error{Kind: KindConflict}
+
+	ErrTooManyRequests = &Error{Kind: KindTooManyRequests}
+)
+
+func (e *Error) Error() string {
+	return e.Localize(i18n.DefaultLocale)
+}
+
+func (e *Error) Localize(locale string) string {
+	if e.Key == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return i18n.Translate(locale, e.Key, e.Args...)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is lets errors.Is(err, domain.ErrNotFound) match any error of the same kind.
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(*Error)
+	return ok && kind.Key == "" && kind.Err == nil && kind.Kind == e.Kind
+}
+
+func Field(field, key string, args ...interface{}) FieldError {
+	return FieldError{Field: field, Key: key, Args: args}
+}
+
+func Validation(key string, args ...interface{}) error {
+	return &Error{Kind: KindValidation, Key: key, Args: args}
+}
+
+func InvalidFields(fields []FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &Error{Kind: KindValidation, Key: "validation.invalid_fields", Fields: fields}
+}
+
+func Unauthorized(key string, args ...interface{}) error {
+	return &Error{Kind: KindUnauthorized, Key: key, Args: args}
+}
+
+func Forbidden(key string, args ...interface{}) error {
+	return &Error{Kind: KindForbidden, Key: key, Args: args}
+}
+
+func NotFound(key string, args ...interface{}) error {
+	return &Error{Kind: KindNotFound, Key: key, Args: args}
+}
+
+func Conflict(key string, args ...interface{}) error {
+	return &Error{Kind: KindConflict, Key: key, Args: args}
+}
+
+func TooManyRequests(retryAfter time.Duration, key string, args ...interface{}) error {
+	return &Error{Kind: KindTooManyRequests, Key: key, Args: args, RetryAfter: retryAfter}
+}
+
+func KindOf(err error) Kind {
+	var domainError *Error
+	if errors.As(err, &domainError) {
+		return domainError.Kind
+	}
+
+	return KindInternal
+}
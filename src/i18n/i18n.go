@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English             = "en"
+	BrazilianPortuguese = "pt-BR"
+	DefaultLocale       = English
+)
+
+var supported = []string{English, BrazilianPortuguese}
+
+func Translate(locale, key string, args ...interface{}) string {
+	message, ok := catalog[locale][key]
+	if !ok {
+		if message, ok = catalog[DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+func FromRequest(r *http.Request) string {
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+func Negotiate(acceptLanguage string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if fields[0] == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, parameter := range fields[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if strings.HasPrefix(parameter, "q=") {
+				if parsed, err := strconv.ParseFloat(parameter[2:], 64); err == nil {
+					quality = parsed
+				}
+			}
+		}
+
+		if quality > 0 {
+			preferences = append(preferences, preference{fields[0], quality})
+		}
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, preference := range preferences {
+		if locale, ok := match(preference.tag); ok {
+			return locale
+		}
+	}
+
+	return DefaultLocale
+}
+
+func match(tag string) (string, bool) {
+	if tag == "*" {
+		return DefaultLocale, true
+	}
+
+	for _, locale := range supported {
+		if strings.EqualFold(tag, locale) {
+			return locale, true
+		}
+	}
+
+	// Fall back to the first supported locale of the same language, so "pt" or
+	// "pt-PT" still get Portuguese and "en-US" gets English.
+	language := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+	for _, locale := range supported {
+		if strings.ToLower(strings.SplitN(locale, "-", 2)[0]) == language {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,270 @@
+package i18n
+
+var catalog = map[string]map[string]string{
+	English: {
+		"http.400": "Bad Request",
+		"http.401": "Unauthorized",
+		"http.403": "Forbidden",
+		"http.404": "Not Found",
+		"http.409": "Conflict",
+		"http.429": "Too Many Requests",
+		"http.499": "Client Closed Request",
+		"http.500": "Internal Server Error",
+		"http.503": "Service Unavailable",
+
+		"request.timeout":           "the request took too long to be processed, try again later",
+		"request.canceled":          "the request was canceled by the client",
+		"request.internal":          "an unexpected error occurred",
+		"request.invalid_parameter": "the %s parameter is invalid",
+		"request.invalid_body":      "the request body is not a valid JSON document",
+
+		"authentication.invalid_token":      "the access token is missing, invalid or expired",
+		"authentication.session":            "the session has expired or was revoked",
+		"authentication.forbidden":          "you do not have permission to do this",
+		"authentication.insufficient_scope": "the token does not grant access to this resource, it requires the %s scope",
+		"login.locked":                      "too many failed sign-in attempts, try again in %d seconds",
+		"login.invalid_credentials":         "the email or password is incorrect",
+		"token.refresh_required":            "the refresh token is mandatory",
+		"token.refresh_invalid":             "the refresh token is invalid, expired or revoked",
+		"password_reset.invalid_token":      "the password reset link is invalid, expired or was already used",
+		"email_verification.invalid_token":  "the email verification link is invalid, expired or was already used",
+		"magic_link.disabled":               "sign-in with a magic link is disabled",
+		"magic_link.invalid_token":          "the sign-in link is invalid, expired or was already used",
+		"magic_link.too_many":               "too many sign-in links were requested for this email, try again in %d seconds",
+		"password_reset.too_many":           "too many password resets were requested, try again in %d seconds",
+		"email.unverified":                  "you must verify your email address before doing this",
+		"email.already_verified":            "the email address is already verified",
+		"email.forbidden_verification":      "you cannot request the verification of an email other than yours",
+		"two_factor.invalid_code":           "the authentication code is invalid",
+		"two_factor.invalid_challenge":      "the login challenge is invalid or expired, sign in again",
+		"two_factor.already_enabled":        "two-factor authentication is already enabled",
+		"two_factor.not_enrolled":           "start the two-factor enrollment before confirming it",
+		"two_factor.not_enabled":            "two-factor authentication is not enabled",
+		"two_factor.forbidden":              "you cannot manage the two-factor authentication of a user other than yours",
+		"oidc.disabled":                     "sign-in with an external provider is not configured",
+		"oidc.unavailable":                  "the identity provider cannot be reached, try again later",
+		"oidc.invalid_state":                "the sign-in request is invalid or expired, start again",
+		"oidc.invalid_login":                "the identity provider did not confirm the sign-in",
+		"oidc.email_unverified":             "the identity provider did not confirm your email address",
+		"oidc.account_unverified":           "an account with this email exists but its address is not verified, verify it or sign in with the password first",
+		"oauth.invalid_client":              "the client is unknown or its credentials are invalid",
+		"oauth.invalid_grant":               "the authorization code or refresh token is invalid, expired or was already used",
+		"oauth.invalid_token":               "the OAuth token is invalid, expired or revoked",
+		"oauth.invalid_request":             "the %s parameter is missing or invalid",
+		"oauth.invalid_scope":               "the requested scope exceeds the one originally granted",
+		"oauth.unsupported_grant_type":      "the grant type must be authorization_code or refresh_token",
+		"oauth.unsupported_response_type":   "the response type must be code",
+		"oauth.redirect_uri_invalid":        "the redirect URI is not registered for this client",
+		"oauth.code_challenge_required":     "a PKCE code challenge with the S256 method is mandatory",
+
+		"record.duplicate":             "the %s is already in use",
+		"record.referenced_not_found":  "the referenced record does not exist",
+		"user.not_found":               "user not found",
+		"user.forbidden_update":        "you cannot change a user other than yours",
+		"user.forbidden_delete":        "you cannot delete a user other than yours",
+		"user.follow_self":             "you cannot follow your own username",
+		"user.unfollow_self":           "you can't stop following your own username",
+		"user.email_change_restricted": "the e-mail can only be changed from a signed-in session",
+		"user.block_self":              "you cannot block your own username",
+		"user.mute_self":               "you cannot mute your own username",
+		"block.forbidden":              "you cannot see the blocked users of a user other than yours",
+		"mute.forbidden":               "you cannot see the muted users of a user other than yours",
+		"report.not_found":             "report not found",
+		"report.self":                  "you cannot report your own username or publications",
+		"report.closed":                "this report has already been decided",
+		"report.duplicate":             "you already reported this and it is waiting for a moderator",
+		"follow_request.not_found":     "follow request not found",
+		"follow_request.forbidden":     "you cannot manage the follow requests of a user other than yours",
+		"user.forbidden_password":      "you are not allowed to update the password of a user other than yours",
+		"user.suspended":               "this account is suspended",
+		"admin.suspend_self":           "you cannot suspend your own account",
+		"admin.suspend_staff":          "only admins can suspend moderators and admins",
+		"admin.role_self":              "you cannot change your own role",
+		"password.incorrect":           "the current password is incorrect",
+		"session.not_found":            "session not found",
+		"session.forbidden":            "you cannot manage the sessions of a user other than yours",
+		"token.not_found":              "token not found",
+		"token.forbidden":              "you cannot manage the tokens of a user other than yours",
+		"oauth.client_not_found":       "client not found",
+		"oauth.forbidden_client":       "you cannot manage the OAuth clients of a user other than yours",
+		"oauth.consent_not_found":      "consent not found",
+		"oauth.forbidden_consent":      "you cannot manage the consents of a user other than yours",
+		"publication.not_found":        "publication not found",
+		"publication.forbidden_update": "you cannot change a post that is not yours",
+		"publication.forbidden_delete": "you cannot delete a post that is not yours",
+		"comment.not_found":            "comment not found",
+		"comment.invalid_parent":       "the comment being replied to does not belong to this publication",
+		"comment.too_deep":             "replies cannot nest more than %d levels deep",
+		"comment.forbidden_update":     "you cannot change a comment that is not yours",
+		"comment.forbidden_delete":     "you cannot delete a comment that is not yours or on your post",
+
+		"pagination.invalid_limit":       "the limit must be a number between 1 and %d",
+		"pagination.invalid_cursor":      "the cursor is invalid",
+		"pagination.conflicting_cursors": "the after and before cursors cannot be used together",
+
+		"validation.invalid_fields":         "one or more fields are invalid",
+		"validation.name_required":          "the name is mandatory and cannot be blank",
+		"validation.nick_required":          "the nick is mandatory and cannot be blank",
+		"validation.email_required":         "the e-mail is mandatory and cannot be blank",
+		"validation.email_invalid":          "the email provided does not correspond to a valid address",
+		"validation.password_required":      "the password is mandatory and cannot be blank",
+		"validation.token_required":         "the token is mandatory and cannot be blank",
+		"validation.code_required":          "the code is mandatory and cannot be blank",
+		"validation.state_required":         "the state is mandatory and cannot be blank",
+		"validation.role_invalid":           "the role must be user, moderator or admin",
+		"validation.name_too_long":          "the name cannot be longer than %d characters",
+		"validation.scopes_required":        "at least one scope is mandatory",
+		"validation.scope_invalid":          "the scope %s does not exist",
+		"validation.expiration_past":        "the expiration must be in the future",
+		"validation.redirect_uris_required": "at least one redirect URI is mandatory",
+		"validation.redirect_uri_invalid":   "the redirect URI %s must use https, or http to a loopback address, have no fragment and be at most 255 characters long",
+		"validation.title_required":         "the title is mandatory and cannot be blank",
+		"validation.content_required":       "the content is mandatory and cannot be blank",
+		"validation.content_too_long":       "the content cannot be longer than %d characters",
+		"validation.reason_required":        "the reason is mandatory and cannot be blank",
+		"validation.reason_invalid":         "the reason must be one of %s",
+		"validation.details_too_long":       "the details cannot be longer than %d characters",
+		"validation.action_invalid":         "the action must be hide, delete, suspend or dismiss, and only publications can be hidden or deleted",
+		"validation.note_too_long":          "the note cannot be longer than %d characters",
+
+		"mail.email_verification.subject": "Confirm your email address",
+		"mail.email_verification.body":    "Use the link below to confirm that this address belongs to you:\r\n%s\r\n\r\nThe link expires in %s. If you did not create an account, you can ignore this email.",
+		"mail.magic_link.subject":         "Your sign-in link",
+		"mail.magic_link.body":            "Use the link below to sign in:\r\n%s\r\n\r\nThe link expires in %s and can only be used once. If you did not ask for it, you can ignore this email.",
+		"mail.password_reset.subject":     "Reset your password",
+		"mail.password_reset.body":        "We received a request to reset your password.\r\n\r\nUse the link below to choose a new one:\r\n%s\r\n\r\nThe link expires in %s and can only be used once. If you did not ask for it, you can ignore this email.",
+	},
+	BrazilianPortuguese: {
+		"http.400": "Requisição inválida",
+		"http.401": "Não autorizado",
+		"http.403": "Proibido",
+		"http.404": "Não encontrado",
+		"http.409": "Conflito",
+		"http.429": "Muitas requisições",
+		"http.499": "Requisição cancelada pelo cliente",
+		"http.500": "Erro interno do servidor",
+		"http.503": "Serviço indisponível",
+
+		"request.timeout":           "a requisição demorou demais para ser processada, tente novamente mais tarde",
+		"request.canceled":          "a requisição foi cancelada pelo cliente",
+		"request.internal":          "ocorreu um erro inesperado",
+		"request.invalid_parameter": "o parâmetro %s é inválido",
+		"request.invalid_body":      "o corpo da requisição não é um documento JSON válido",
+
+		"authentication.invalid_token":      "o token de acesso está ausente, é inválido ou expirou",
+		"authentication.session":            "a sessão expirou ou foi revogada",
+		"authentication.forbidden":          "você não tem permissão para fazer isso",
+		"authentication.insufficient_scope": "o token não dá acesso a este recurso, que exige o escopo %s",
+		"login.locked":                      "muitas tentativas de login sem sucesso, tente novamente em %d segundos",
+		"login.invalid_credentials":         "o e-mail ou a senha estão incorretos",
+		"token.refresh_required":            "o token de renovação é obrigatório",
+		"token.refresh_invalid":             "o token de renovação é inválido, expirou ou foi revogado",
+		"password_reset.invalid_token":      "o link de redefinição de senha é inválido, expirou ou já foi utilizado",
+		"email_verification.invalid_token":  "o link de verificação de e-mail é inválido, expirou ou já foi utilizado",
+		"magic_link.disabled":               "o login por link mágico está desativado",
+		"magic_link.invalid_token":          "o link de login é inválido, expirou ou já foi utilizado",
+		"magic_link.too_many":               "muitos links de login foram solicitados para este e-mail, tente novamente em %d segundos",
+		"password_reset.too_many":           "muitas redefinições de senha foram solicitadas, tente novamente em %d segundos",
+		"email.unverified":                  "você precisa verificar o seu e-mail antes de fazer isso",
+		"email.already_verified":            "o e-mail já está verificado",
+		"email.forbidden_verification":      "você não pode solicitar a verificação de um e-mail que não seja o seu",
+		"two_factor.invalid_code":           "o código de autenticação é inválido",
+		"two_factor.invalid_challenge":      "o desafio de login é inválido ou expirou, entre novamente",
+		"two_factor.already_enabled":        "a autenticação em dois fatores já está ativada",
+		"two_factor.not_enrolled":           "inicie o cadastro da autenticação em dois fatores antes de confirmá-lo",
+		"two_factor.not_enabled":            "a autenticação em dois fatores não está ativada",
+		"two_factor.forbidden":              "você não pode gerenciar a autenticação em dois fatores de um usuário que não seja o seu",
+		"oidc.disabled":                     "o login com um provedor externo não está configurado",
+		"oidc.unavailable":                  "o provedor de identidade está inacessível, tente novamente mais tarde",
+		"oidc.invalid_state":                "a solicitação de login é inválida ou expirou, comece novamente",
+		"oidc.invalid_login":                "o provedor de identidade não confirmou o login",
+		"oidc.email_unverified":             "o provedor de identidade não confirmou o seu e-mail",
+		"oidc.account_unverified":           "já existe uma conta com este e-mail, mas o endereço não foi verificado, verifique-o ou entre com a senha primeiro",
+		"oauth.invalid_client":              "o cliente é desconhecido ou as suas credenciais são inválidas",
+		"oauth.invalid_grant":               "o código de autorização ou o token de renovação é inválido, expirou ou já foi utilizado",
+		"oauth.invalid_token":               "o token OAuth é inválido, expirou ou foi revogado",
+		"oauth.invalid_request":             "o parâmetro %s está ausente ou é inválido",
+		"oauth.invalid_scope":               "o escopo solicitado excede o concedido originalmente",
+		"oauth.unsupported_grant_type":      "o tipo de concessão deve ser authorization_code ou refresh_token",
+		"oauth.unsupported_response_type":   "o tipo de resposta deve ser code",
+		"oauth.redirect_uri_invalid":        "a URI de redirecionamento não está registrada para este cliente",
+		"oauth.code_challenge_required":     "um desafio PKCE com o método S256 é obrigatório",
+
+		"record.duplicate":             "o campo %s já está em uso",
+		"record.referenced_not_found":  "o registro referenciado não existe",
+		"user.not_found":               "usuário não encontrado",
+		"user.forbidden_update":        "você não pode alterar um usuário que não seja o seu",
+		"user.forbidden_delete":        "você não pode excluir um usuário que não seja o seu",
+		"user.follow_self":             "você não pode seguir o seu próprio usuário",
+		"user.unfollow_self":           "você não pode deixar de seguir o seu próprio usuário",
+		"user.email_change_restricted": "o e-mail só pode ser alterado a partir de uma sessão autenticada",
+		"user.block_self":              "você não pode bloquear o seu próprio usuário",
+		"user.mute_self":               "você não pode silenciar o seu próprio usuário",
+		"block.forbidden":              "você não pode ver os usuários bloqueados de um usuário que não seja o seu",
+		"mute.forbidden":               "você não pode ver os usuários silenciados de um usuário que não seja o seu",
+		"report.not_found":             "denúncia não encontrada",
+		"report.self":                  "você não pode denunciar o seu próprio usuário ou publicações",
+		"report.closed":                "esta denúncia já foi decidida",
+		"report.duplicate":             "você já denunciou isto e a denúncia aguarda um moderador",
+		"follow_request.not_found":     "solicitação para seguir não encontrada",
+		"follow_request.forbidden":     "você não pode gerenciar as solicitações para seguir de um usuário que não seja o seu",
+		"user.forbidden_password":      "você não pode atualizar a senha de um usuário que não seja o seu",
+		"user.suspended":               "esta conta está suspensa",
+		"admin.suspend_self":           "você não pode suspender a sua própria conta",
+		"admin.suspend_staff":          "apenas administradores podem suspender moderadores e administradores",
+		"admin.role_self":              "você não pode alterar o seu próprio papel",
+		"password.incorrect":           "a senha atual está incorreta",
+		"session.not_found":            "sessão não encontrada",
+		"session.forbidden":            "você não pode gerenciar as sessões de um usuário que não seja o seu",
+		"token.not_found":              "token não encontrado",
+		"token.forbidden":              "você não pode gerenciar os tokens de um usuário que não seja o seu",
+		"oauth.client_not_found":       "cliente não encontrado",
+		"oauth.forbidden_client":       "você não pode gerenciar os clientes OAuth de um usuário que não seja o seu",
+		"oauth.consent_not_found":      "consentimento não encontrado",
+		"oauth.forbidden_consent":      "você não pode gerenciar os consentimentos de um usuário que não seja o seu",
+		"publication.not_found":        "publicação não encontrada",
+		"publication.forbidden_update": "você não pode alterar uma publicação que não é sua",
+		"publication.forbidden_delete": "você não pode excluir uma publicação que não é sua",
+		"comment.not_found":            "comentário não encontrado",
+		"comment.invalid_parent":       "o comentário respondido não pertence a esta publicação",
+		"comment.too_deep":             "as respostas não podem ter mais de %d níveis",
+		"comment.forbidden_update":     "você não pode alterar um comentário que não é seu",
+		"comment.forbidden_delete":     "você não pode excluir um comentário que não é seu nem está na sua publicação",
+
+		"pagination.invalid_limit":       "o limite deve ser um número entre 1 e %d",
+		"pagination.invalid_cursor":      "o cursor é inválido",
+		"pagination.conflicting_cursors": "os cursores after e before não podem ser usados juntos",
+
+		"validation.invalid_fields":         "um ou mais campos são inválidos",
+		"validation.name_required":          "o nome é obrigatório e não pode estar em branco",
+		"validation.nick_required":          "o nick é obrigatório e não pode estar em branco",
+		"validation.email_required":         "o e-mail é obrigatório e não pode estar em branco",
+		"validation.email_invalid":          "o e-mail informado não corresponde a um endereço válido",
+		"validation.password_required":      "a senha é obrigatória e não pode estar em branco",
+		"validation.token_required":         "o token é obrigatório e não pode estar em branco",
+		"validation.code_required":          "o código é obrigatório e não pode estar em branco",
+		"validation.state_required":         "o state é obrigatório e não pode estar em branco",
+		"validation.role_invalid":           "o papel deve ser user, moderator ou admin",
+		"validation.name_too_long":          "o nome não pode ter mais de %d caracteres",
+		"validation.scopes_required":        "pelo menos um escopo é obrigatório",
+		"validation.scope_invalid":          "o escopo %s não existe",
+		"validation.expiration_past":        "a expiração deve estar no futuro",
+		"validation.redirect_uris_required": "pelo menos uma URI de redirecionamento é obrigatória",
+		"validation.redirect_uri_invalid":   "a URI de redirecionamento %s deve usar https, ou http para um endereço de loopback, não ter fragmento e ter no máximo 255 caracteres",
+		"validation.title_required":         "o título é obrigatório e não pode estar em branco",
+		"validation.content_required":       "o conteúdo é obrigatório e não pode estar em branco",
+		"validation.content_too_long":       "o conteúdo não pode ter mais de %d caracteres",
+		"validation.reason_required":        "o motivo é obrigatório e não pode estar em branco",
+		"validation.reason_invalid":         "o motivo deve ser um de %s",
+		"validation.details_too_long":       "os detalhes não podem ter mais de %d caracteres",
+		"validation.action_invalid":         "a ação deve ser hide, delete, suspend ou dismiss, e apenas publicações podem ser ocultadas ou excluídas",
+		"validation.note_too_long":          "a nota não pode ter mais de %d caracteres",
+
+		"mail.email_verification.subject": "Confirme o seu e-mail",
+		"mail.email_verification.body":    "Use o link abaixo para confirmar que este endereço pertence a você:\r\n%s\r\n\r\nO link expira em %s. Se você não criou uma conta, ignore este e-mail.",
+		"mail.magic_link.subject":         "Seu link de login",
+		"mail.magic_link.body":            "Use o link abaixo para entrar:\r\n%s\r\n\r\nO link expira em %s e só pode ser usado uma vez. Se você não fez essa solicitação, ignore este e-mail.",
+		"mail.password_reset.subject":     "Redefina sua senha",
+		"mail.password_reset.body":        "Recebemos uma solicitação para redefinir a sua senha.\r\n\r\nUse o link abaixo para escolher uma nova:\r\n%s\r\n\r\nO link expira em %s e só pode ser usado uma vez. Se você não fez essa solicitação, ignore este e-mail.",
+	},
+}
@@ -0,0 +1,114 @@
+package mail
+
+import (
+	"api/src/config"
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+func New() (Mailer, error) {
+	switch config.MailDriver {
+	case "smtp":
+		return NewSMTPMailer(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, config.MailFrom), nil
+	case "file":
+		return NewFileMailer(config.MailDirectory, config.MailFrom), nil
+	case "log":
+		return NewLogMailer(config.MailFrom), nil
+	}
+
+	return nil, fmt.Errorf("unknown mail driver %q, expected smtp, file or log", config.MailDriver)
+}
+
+type SMTPMailer struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{fmt.Sprintf("%s:%d", host, port), auth, from}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.address, m.auth, m.from, []string{message.To}, format(m.from, message))
+}
+
+type FileMailer struct {
+	directory string
+	from      string
+}
+
+func NewFileMailer(directory, from string) *FileMailer {
+	return &FileMailer{directory, from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, message Message) error {
+	if err := os.MkdirAll(m.directory, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), fileSafe(strings.ReplaceAll(message.To, "@", "_at_")))
+	return os.WriteFile(filepath.Join(m.directory, name), format(m.from, message), 0o600)
+}
+
+// fileSafe keeps an address from naming a path outside the mail directory.
+func fileSafe(value string) string {
+	return strings.Map(func(character rune) rune {
+		if character < 128 && (unicode.IsLetter(character) || unicode.IsDigit(character) || strings.ContainsRune("._-+", character)) {
+			return character
+		}
+
+		return '_'
+	}, value)
+}
+
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	log.Printf("\n%s", format(m.from, message))
+	return nil
+}
+
+func format(from string, message Message) []byte {
+	return []byte(strings.Join([]string{
+		"From: " + from,
+		"To: " + message.To,
+		"Subject: " + message.Subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		message.Body,
+	}, "\r\n"))
+}
@@ -3,10 +3,29 @@ package middlewares
 import (
 	"api/src/answers"
 	"api/src/authentication"
+	"api/src/domain"
+	"api/src/repositories"
+	"api/src/security"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
+var errInvalidToken = domain.Unauthorized("authentication.invalid_token")
+
+type Authenticator struct {
+	sessions repositories.Sessions
+	users    repositories.Users
+	tokens   repositories.PersonalAccessTokens
+	oauth    repositories.OAuthTokens
+}
+
+func NewAuthenticator(sessions repositories.Sessions, users repositories.Users, tokens repositories.PersonalAccessTokens, oauth repositories.OAuthTokens) *Authenticator {
+	return &Authenticator{sessions, users, tokens, oauth}
+}
+
 func Logger(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("\n %s %s %s", r.Method, r.RequestURI, r.Host)
@@ -14,12 +33,177 @@ func Logger(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-func Authenticate(next http.HandlerFunc) http.HandlerFunc {
+func (a *Authenticator) Authenticate(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if err := authentication.ValidateToken(r); err != nil {
-			answers.Err(w, http.StatusUnauthorized, err)
+		authenticate := a.authenticateSession
+		switch token := authentication.BearerToken(r); {
+		case strings.HasPrefix(token, authentication.PersonalAccessTokenPrefix):
+			authenticate = a.authenticatePersonalAccessToken
+		case strings.HasPrefix(token, authentication.OAuthAccessTokenPrefix):
+			authenticate = a.authenticateOAuthToken
+		}
+
+		principal, err := authenticate(r)
+		if err != nil {
+			answers.Error(w, r, err)
 			return
 		}
+
+		next(w, r.WithContext(authentication.NewContext(r.Context(), principal)))
+	}
+}
+
+func (a *Authenticator) authenticateSession(r *http.Request) (authentication.Principal, error) {
+	claims, err := authentication.ParseToken(r)
+	if err != nil {
+		return authentication.Principal{}, errInvalidToken
+	}
+
+	principal, err := claims.Principal()
+	if err != nil {
+		return authentication.Principal{}, errInvalidToken
+	}
+
+	active, err := a.sessions.Active(r.Context(), principal.SessionID, principal.UserID)
+	if err != nil {
+		return authentication.Principal{}, err
+	}
+
+	if !active {
+		return authentication.Principal{}, domain.Unauthorized("authentication.session")
+	}
+
+	// Suspending revokes the sessions too, but an access token issued just
+	// before must not outlive the suspension.
+	user, err := a.users.SearchID(r.Context(), principal.UserID)
+	if err != nil {
+		return authentication.Principal{}, err
+	}
+
+	if user.SuspendedAt != nil {
+		return authentication.Principal{}, domain.Forbidden("user.suspended")
+	}
+
+	if err = a.sessions.Touch(r.Context(), principal.SessionID); err != nil {
+		return authentication.Principal{}, err
+	}
+
+	return principal, nil
+}
+
+// authenticatePersonalAccessToken never grants a role: personal access tokens
+// only reach the routes allowed by their scopes.
+func (a *Authenticator) authenticatePersonalAccessToken(r *http.Request) (authentication.Principal, error) {
+	token, err := a.tokens.SearchToken(r.Context(), security.HashToken(authentication.BearerToken(r)))
+	if errors.Is(err, domain.ErrNotFound) {
+		return authentication.Principal{}, errInvalidToken
+	}
+	if err != nil {
+		return authentication.Principal{}, err
+	}
+
+	user, err := a.users.SearchID(r.Context(), token.UserID)
+	if err != nil {
+		return authentication.Principal{}, err
+	}
+
+	if user.SuspendedAt != nil {
+		return authentication.Principal{}, domain.Forbidden("user.suspended")
+	}
+
+	if err = a.tokens.Touch(r.Context(), token.ID); err != nil {
+		return authentication.Principal{}, err
+	}
+
+	return authentication.Principal{
+		UserID:     token.UserID,
+		TokenID:    strconv.FormatUint(token.ID, 10),
+		Scopes:     token.Scopes,
+		Restricted: true,
+	}, nil
+}
+
+// authenticateOAuthToken, like personal access tokens, restricts the client to
+// the scopes the user consented to.
+func (a *Authenticator) authenticateOAuthToken(r *http.Request) (authentication.Principal, error) {
+	token, err := a.oauth.SearchAccessToken(r.Context(), security.HashToken(authentication.BearerToken(r)))
+	if errors.Is(err, domain.ErrNotFound) {
+		return authentication.Principal{}, errInvalidToken
+	}
+	if err != nil {
+		return authentication.Principal{}, err
+	}
+
+	user, err := a.users.SearchID(r.Context(), token.UserID)
+	if err != nil {
+		return authentication.Principal{}, err
+	}
+
+	if user.SuspendedAt != nil {
+		return authentication.Principal{}, domain.Forbidden("user.suspended")
+	}
+
+	return authentication.Principal{
+		UserID:     token.UserID,
+		TokenID:    strconv.FormatUint(token.ID, 10),
+		Scopes:     token.Scopes,
+		Restricted: true,
+	}, nil
+}
+
+func (a *Authenticator) RequireVerifiedEmail(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := authentication.ExtractUserID(r)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		user, err := a.users.SearchID(r.Context(), userID)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		if !user.EmailVerified {
+			answers.Error(w, r, domain.Forbidden("email.unverified"))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (a *Authenticator) Authorize(permission string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authentication.PrincipalFromRequest(r)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		if !principal.Can(permission) {
+			answers.Error(w, r, domain.Forbidden("authentication.forbidden"))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (a *Authenticator) RequireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authentication.PrincipalFromRequest(r)
+		if err != nil {
+			answers.Error(w, r, err)
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			answers.Error(w, r, domain.Forbidden("authentication.insufficient_scope", scope))
+			return
+		}
+
 		next(w, r)
 	}
 }
@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"api/src/authentication"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serve(handler http.HandlerFunc, principal *authentication.Principal) int {
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	if principal != nil {
+		r = r.WithContext(authentication.NewContext(r.Context(), *principal))
+	}
+
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w.Code
+}
+
+func TestRequireScope(t *testing.T) {
+	session := &authentication.Principal{UserID: 1, SessionID: 1, Role: authentication.RoleUser}
+	token := &authentication.Principal{UserID: 1, TokenID: "1", Role: authentication.RoleAdmin, Restricted: true,
+		Scopes: []string{authentication.ScopeUsersRead, authentication.ScopeUsersWrite}}
+
+	tests := []struct {
+		name       string
+		scope      string
+		principal  *authentication.Principal
+		wantStatus int
+	}{
+		{"anonymous", authentication.ScopeUsersRead, nil, http.StatusUnauthorized},
+		{"session on a scoped route", authentication.ScopeUsersRead, session, http.StatusOK},
+		{"session on a route without scope", "", session, http.StatusOK},
+		{"token with the scope", authentication.ScopeUsersWrite, token, http.StatusOK},
+		{"token without the scope", authentication.ScopePublicationsWrite, token, http.StatusForbidden},
+		// Sessions, tokens, two-factor and admin routes declare no scope, so
+		// delegated tokens never reach them whatever they were granted.
+		{"token on a route without scope", "", token, http.StatusForbidden},
+	}
+
+	authenticator := &Authenticator{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			called := false
+			handler := authenticator.RequireScope(test.scope, func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			if status := serve(handler, test.principal); status != test.wantStatus {
+				t.Errorf("status = %d, want %d", status, test.wantStatus)
+			}
+
+			if called != (test.wantStatus == http.StatusOK) {
+				t.Errorf("handler called = %v with status %d", called, test.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *authentication.Principal
+		wantStatus int
+	}{
+		{"anonymous", nil, http.StatusUnauthorized},
+		{"user", &authentication.Principal{UserID: 1, Role: authentication.RoleUser}, http.StatusForbidden},
+		{"moderator", &authentication.Principal{UserID: 1, Role: authentication.RoleModerator}, http.StatusOK},
+		{"admin", &authentication.Principal{UserID: 1, Role: authentication.RoleAdmin}, http.StatusOK},
+	}
+
+	authenticator := &Authenticator{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := authenticator.Authorize(authentication.PermissionSuspendAccounts, func(w http.ResponseWriter, r *http.Request) {})
+
+			if status := serve(handler, test.principal); status != test.wantStatus {
+				t.Errorf("status = %d, want %d", status, test.wantStatus)
+			}
+		})
+	}
+}
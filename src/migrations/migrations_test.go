@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"only comments", "-- nothing to do\n  -- really\n", nil},
+		{"single statement", "drop table users;", []string{"drop table users"}},
+		{
+			"several statements",
+			"create table a (id int);\n\ncreate table b (id int);\n",
+			[]string{"create table a (id int)", "create table b (id int)"},
+		},
+		{
+			"multiline statement",
+			"create table users (\n\tid int,\n\tname varchar(50)\n) ENGINE=INNODB;",
+			[]string{"create table users (\n\tid int,\n\tname varchar(50)\n) ENGINE=INNODB"},
+		},
+		{
+			"comments between lines",
+			"-- users\ncreate table users (\n\t-- the key\n\tid int\n);\n-- done",
+			[]string{"create table users (\n\tid int\n)"},
+		},
+		{
+			"trailing spaces after the semicolon",
+			"alter table users add column bio text;   \ndrop index nick on users;\t",
+			[]string{"alter table users add column bio text", "drop index nick on users"},
+		},
+		{
+			"semicolon inside a line",
+			"insert into settings values ('a;b');\n",
+			[]string{"insert into settings values ('a;b')"},
+		},
+		{"missing final semicolon", "create table a (id int);\ndrop table b", []string{"create table a (id int)", "drop table b"}},
+		{"windows line endings", "drop table a;\r\ndrop table b;\r\n", []string{"drop table a", "drop table b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := statements(test.script); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("statements(%q) = %q, want %q", test.script, got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("Load found no migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != uint64(i+1) {
+			t.Errorf("migration %d has version %04d, want versions without gaps", i, migration.Version)
+		}
+
+		if migration.Down == "" {
+			t.Errorf("migration %04d_%s has no down script", migration.Version, migration.Name)
+		}
+
+		if len(statements(migration.Up)) == 0 {
+			t.Errorf("migration %04d_%s has no up statements", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+CREATE TABLE users (
+    id int auto_increment primary key,
+    name varchar(50) not null,
+    nick varchar(50) not null unique,
+    email varchar(50) not null unique,
+    password varchar(100) not null unique,
+    createdat timestamp default current_timestamp()
+) ENGINE=INNODB;
+
+CREATE TABLE followers (
+    user_id int not null,
+    FOREIGN KEY (user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    follower_id int not null,
+    FOREIGN KEY (follower_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+    PRIMARY KEY (user_id, follower_id)
+) ENGINE=INNODB;
+
+CREATE TABLE publications (
+    id int auto_increment primary key,
+    title varchar(50) not null,
+    content varchar(300) not null,
+
+    author_id int not null,
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE sessions (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    refresh_token varchar(64) not null unique,
+    expires_at timestamp not null,
+    revoked_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE signing_keys (
+    id varchar(64) primary key,
+    algorithm varchar(10) not null,
+    private_key text not null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE likes (
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    publication_id int not null,
+    FOREIGN KEY (publication_id) REFERENCES publications (id) ON DELETE CASCADE,
+
+    createdat timestamp default current_timestamp,
+    PRIMARY KEY (user_id, publication_id)
+) ENGINE=INNODB;
+
+CREATE TABLE comments (
+    id int auto_increment primary key,
+    publication_id int not null,
+    FOREIGN KEY (publication_id) REFERENCES publications (id) ON DELETE CASCADE,
+
+    parent_id int null,
+    FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE,
+
+    author_id int not null,
+    FOREIGN KEY (author_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    content varchar(300) not null,
+    createdat timestamp default current_timestamp,
+    updatedat timestamp default current_timestamp on update current_timestamp
+) ENGINE=INNODB;
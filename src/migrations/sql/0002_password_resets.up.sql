@@ -0,0 +1,10 @@
+CREATE TABLE password_resets (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    token varchar(64) not null unique,
+    expires_at timestamp not null,
+    used_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
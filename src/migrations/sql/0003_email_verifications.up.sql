@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN email_verified boolean not null default false;
+
+CREATE TABLE email_verifications (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    email varchar(50) not null,
+    token varchar(64) not null unique,
+    expires_at timestamp not null,
+    used_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
@@ -0,0 +1,28 @@
+ALTER TABLE users
+    ADD COLUMN totp_secret varchar(64) not null default '',
+    ADD COLUMN totp_enabled boolean not null default false,
+    ADD COLUMN totp_last_step bigint not null default 0;
+
+CREATE TABLE recovery_codes (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    code varchar(64) not null,
+    used_at timestamp null default null,
+    createdat timestamp default current_timestamp,
+
+    UNIQUE KEY (user_id, code)
+) ENGINE=INNODB;
+
+CREATE TABLE login_challenges (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    token varchar(64) not null unique,
+    attempts int not null default 0,
+    expires_at timestamp not null,
+    used_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
@@ -0,0 +1,15 @@
+CREATE TABLE login_attempts (
+    id int auto_increment primary key,
+    user_id int null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL,
+
+    email varchar(50) not null,
+    ip varchar(45) not null,
+    user_agent varchar(255) not null default '',
+    success boolean not null,
+    reason varchar(32) not null,
+    createdat timestamp default current_timestamp,
+
+    INDEX (email, createdat),
+    INDEX (ip, createdat)
+) ENGINE=INNODB;
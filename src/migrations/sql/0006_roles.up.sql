@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN role varchar(16) not null default 'user',
+    ADD COLUMN suspended_at timestamp null default null;
@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    DROP COLUMN last_seen_at,
+    DROP COLUMN ip,
+    DROP COLUMN user_agent,
+    DROP COLUMN token_id;
@@ -0,0 +1,5 @@
+ALTER TABLE sessions
+    ADD COLUMN token_id varchar(64) not null default '',
+    ADD COLUMN user_agent varchar(255) not null default '',
+    ADD COLUMN ip varchar(45) not null default '',
+    ADD COLUMN last_seen_at timestamp not null default current_timestamp;
@@ -0,0 +1,13 @@
+CREATE TABLE personal_access_tokens (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    name varchar(50) not null,
+    token varchar(64) not null unique,
+    scopes varchar(255) not null,
+    expires_at timestamp null default null,
+    last_used_at timestamp null default null,
+    revoked_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
@@ -0,0 +1,60 @@
+CREATE TABLE oauth_clients (
+    id int auto_increment primary key,
+    owner_id int not null,
+    FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    client_id varchar(64) not null unique,
+    secret varchar(64) not null default '',
+    name varchar(50) not null,
+    redirect_uris text not null,
+    confidential boolean not null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE oauth_consents (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    client_id int not null,
+    FOREIGN KEY (client_id) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+
+    scopes varchar(255) not null,
+    createdat timestamp default current_timestamp,
+    updatedat timestamp default current_timestamp on update current_timestamp,
+
+    UNIQUE KEY (user_id, client_id)
+) ENGINE=INNODB;
+
+CREATE TABLE oauth_codes (
+    id int auto_increment primary key,
+    client_id int not null,
+    FOREIGN KEY (client_id) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    code varchar(64) not null unique,
+    redirect_uri varchar(255) not null,
+    scopes varchar(255) not null,
+    code_challenge varchar(128) not null,
+    expires_at timestamp not null,
+    used_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE oauth_tokens (
+    id int auto_increment primary key,
+    client_id int not null,
+    FOREIGN KEY (client_id) REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    code_id int null,
+    FOREIGN KEY (code_id) REFERENCES oauth_codes (id) ON DELETE SET NULL,
+
+    access_token varchar(64) not null unique,
+    refresh_token varchar(64) not null unique,
+    scopes varchar(255) not null,
+    expires_at timestamp not null,
+    refresh_expires_at timestamp not null,
+    revoked_at timestamp null default null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
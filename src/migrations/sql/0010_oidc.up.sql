@@ -0,0 +1,20 @@
+CREATE TABLE oidc_logins (
+    id int auto_increment primary key,
+    state varchar(64) not null unique,
+    nonce varchar(64) not null,
+    code_verifier varchar(64) not null,
+    expires_at timestamp not null,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
+
+CREATE TABLE user_identities (
+    id int auto_increment primary key,
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    issuer varchar(255) not null,
+    subject varchar(255) not null,
+    createdat timestamp default current_timestamp,
+
+    UNIQUE KEY (issuer, subject)
+) ENGINE=INNODB;
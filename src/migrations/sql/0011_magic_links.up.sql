@@ -0,0 +1,13 @@
+CREATE TABLE magic_links (
+    id int auto_increment primary key,
+    user_id int null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+
+    email varchar(50) not null,
+    token varchar(64) null unique,
+    expires_at timestamp not null,
+    used_at timestamp null default null,
+    createdat timestamp default current_timestamp,
+
+    INDEX (email, createdat)
+) ENGINE=INNODB;
@@ -0,0 +1,12 @@
+ALTER TABLE users
+    ADD COLUMN private boolean not null default false;
+
+CREATE TABLE follow_requests (
+    user_id int not null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    follower_id int not null,
+    FOREIGN KEY (follower_id) REFERENCES users (id) ON DELETE CASCADE,
+    createdat timestamp default current_timestamp,
+
+    PRIMARY KEY (user_id, follower_id)
+) ENGINE=INNODB;
@@ -0,0 +1,20 @@
+CREATE TABLE blocks (
+    blocker_id int not null,
+    FOREIGN KEY (blocker_id) REFERENCES users (id) ON DELETE CASCADE,
+    blocked_id int not null,
+    FOREIGN KEY (blocked_id) REFERENCES users (id) ON DELETE CASCADE,
+    createdat timestamp default current_timestamp,
+
+    PRIMARY KEY (blocker_id, blocked_id),
+    INDEX (blocked_id)
+) ENGINE=INNODB;
+
+CREATE TABLE mutes (
+    muter_id int not null,
+    FOREIGN KEY (muter_id) REFERENCES users (id) ON DELETE CASCADE,
+    muted_id int not null,
+    FOREIGN KEY (muted_id) REFERENCES users (id) ON DELETE CASCADE,
+    createdat timestamp default current_timestamp,
+
+    PRIMARY KEY (muter_id, muted_id)
+) ENGINE=INNODB;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS report_actions;
+DROP TABLE IF EXISTS reports;
+
+ALTER TABLE publications
+    DROP COLUMN hidden_at;
@@ -0,0 +1,39 @@
+ALTER TABLE publications
+    ADD COLUMN hidden_at timestamp null default null;
+
+CREATE TABLE reports (
+    id int auto_increment primary key,
+    reporter_id int null,
+    FOREIGN KEY (reporter_id) REFERENCES users (id) ON DELETE SET NULL,
+    user_id int null,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL,
+    publication_id int null,
+    FOREIGN KEY (publication_id) REFERENCES publications (id) ON DELETE SET NULL,
+
+    kind varchar(16) not null,
+    reason varchar(32) not null,
+    details varchar(500) not null default '',
+    status varchar(16) not null default 'open',
+
+    -- What was reported, kept after the account or the publication is deleted.
+    user_nick varchar(50) not null default '',
+    publication_title varchar(50) not null default '',
+    publication_content text null,
+
+    decided_at timestamp null default null,
+    createdat timestamp default current_timestamp,
+
+    INDEX (status, createdat)
+) ENGINE=INNODB;
+
+CREATE TABLE report_actions (
+    id int auto_increment primary key,
+    report_id int not null,
+    FOREIGN KEY (report_id) REFERENCES reports (id) ON DELETE RESTRICT,
+    moderator_id int null,
+    FOREIGN KEY (moderator_id) REFERENCES users (id) ON DELETE SET NULL,
+
+    action varchar(16) not null,
+    note varchar(500) not null default '',
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
@@ -0,0 +1,9 @@
+DELETE FROM password_resets WHERE user_id IS NULL OR token IS NULL;
+
+ALTER TABLE password_resets
+    DROP INDEX email,
+    DROP INDEX ip,
+    DROP COLUMN email,
+    DROP COLUMN ip,
+    MODIFY user_id int not null,
+    MODIFY token varchar(64) not null;
@@ -0,0 +1,7 @@
+ALTER TABLE password_resets
+    MODIFY user_id int null,
+    MODIFY token varchar(64) null,
+    ADD COLUMN email varchar(50) not null default '',
+    ADD COLUMN ip varchar(45) not null default '',
+    ADD INDEX (email, createdat),
+    ADD INDEX (ip, createdat);
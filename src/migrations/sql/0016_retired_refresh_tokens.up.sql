@@ -0,0 +1,6 @@
+CREATE TABLE retired_refresh_tokens (
+    refresh_token varchar(64) primary key,
+    session_id int not null,
+    FOREIGN KEY (session_id) REFERENCES sessions (id) ON DELETE CASCADE,
+    createdat timestamp default current_timestamp
+) ENGINE=INNODB;
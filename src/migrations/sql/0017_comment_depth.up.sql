@@ -0,0 +1,12 @@
+ALTER TABLE comments ADD COLUMN depth tinyint unsigned not null default 0;
+
+UPDATE comments c
+INNER JOIN (
+    WITH RECURSIVE thread (id, depth) AS (
+        SELECT id, CAST(0 AS UNSIGNED) FROM comments WHERE parent_id IS NULL
+        UNION ALL
+        SELECT r.id, t.depth + 1 FROM comments r INNER JOIN thread t ON r.parent_id = t.id
+    )
+    SELECT id, depth FROM thread
+) d ON d.id = c.id
+SET c.depth = LEAST(d.depth, 255);
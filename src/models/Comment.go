@@ -0,0 +1,54 @@
+package models
+
+import (
+	"api/src/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const maxCommentLength = 300
+
+// MaxCommentDepth caps how deep replies nest. Deleting a user cascades through
+// publications, then comments, then one level per reply, and MySQL refuses
+// cascades nested more than 15 levels deep.
+const MaxCommentDepth = 10
+
+type Comment struct {
+	ID            uint64    `json:"id,omitempty"`
+	PublicationID uint64    `json:"publicationid,omitempty"`
+	ParentID      uint64    `json:"parentid,omitempty"`
+	AuthorID      uint64    `json:"authorid,omitempty"`
+	AuthorNick    string    `json:"authornick,omitempty"`
+	Content       string    `json:"content,omitempty"`
+	Depth         uint64    `json:"depth"`
+	Replies       uint64    `json:"replies"`
+	CreatedAt     time.Time `json:"createdat,omitempty"`
+	UpdatedAt     time.Time `json:"updatedat,omitempty"`
+}
+
+func (comment *Comment) Prepare() error {
+	comment.format()
+
+	if err := comment.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (comment *Comment) validate() error {
+	var violations []domain.FieldError
+
+	if comment.Content == "" {
+		violations = append(violations, domain.Field("content", "validation.content_required"))
+	} else if utf8.RuneCountInString(comment.Content) > maxCommentLength {
+		violations = append(violations, domain.Field("content", "validation.content_too_long", maxCommentLength))
+	}
+
+	return domain.InvalidFields(violations)
+}
+
+func (comment *Comment) format() {
+	comment.Content = strings.TrimSpace(comment.Content)
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type FollowRequest struct {
+	UserID       uint64    `json:"userid,omitempty"`
+	FollowerID   uint64    `json:"followerid,omitempty"`
+	FollowerName string    `json:"followername,omitempty"`
+	FollowerNick string    `json:"followernick,omitempty"`
+	CreatedAt    time.Time `json:"createdat,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Like struct {
+	PublicationID uint64    `json:"publicationid,omitempty"`
+	UserID        uint64    `json:"userid,omitempty"`
+	UserName      string    `json:"username,omitempty"`
+	UserNick      string    `json:"usernick,omitempty"`
+	CreatedAt     time.Time `json:"createdat,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type LoginAttempt struct {
+	ID        uint64    `json:"id,omitempty"`
+	UserID    uint64    `json:"userid,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"useragent,omitempty"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdat,omitempty"`
+}
+
+type LoginFailures struct {
+	Count  int
+	LastAt time.Time
+}
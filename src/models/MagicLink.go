@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type MagicLink struct {
+	ID        uint64    `json:"id,omitempty"`
+	UserID    uint64    `json:"userid,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Token     string    `json:"token,omitempty"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresat,omitempty"`
+	CreatedAt time.Time `json:"createdat,omitempty"`
+}
+
+// Requests counts the requests made within a window, for rate limiting.
+type Requests struct {
+	Count   int
+	FirstAt time.Time
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"api/src/domain"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const maxRedirectURILength = 255
+
+type OAuthClient struct {
+	ID           uint64    `json:"-"`
+	OwnerID      uint64    `json:"owner_id,omitempty"`
+	ClientID     string    `json:"client_id,omitempty"`
+	ClientSecret string    `json:"client_secret,omitempty"`
+	SecretHash   string    `json:"-"`
+	Name         string    `json:"name,omitempty"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+}
+
+func (client *OAuthClient) Prepare() error {
+	client.Name = strings.TrimSpace(client.Name)
+	for i, redirectURI := range client.RedirectURIs {
+		client.RedirectURIs[i] = strings.TrimSpace(redirectURI)
+	}
+
+	return client.validate()
+}
+
+func (client *OAuthClient) validate() error {
+	var violations []domain.FieldError
+
+	if client.Name == "" {
+		violations = append(violations, domain.Field("name", "validation.name_required"))
+	} else if len([]rune(client.Name)) > maxTokenNameLength {
+		violations = append(violations, domain.Field("name", "validation.name_too_long", maxTokenNameLength))
+	}
+
+	if len(client.RedirectURIs) == 0 {
+		violations = append(violations, domain.Field("redirect_uris", "validation.redirect_uris_required"))
+	}
+	for _, redirectURI := range client.RedirectURIs {
+		if !validRedirectURI(redirectURI) {
+			violations = append(violations, domain.Field("redirect_uris", "validation.redirect_uri_invalid", redirectURI))
+		}
+	}
+
+	return domain.InvalidFields(violations)
+}
+
+// validRedirectURI only accepts https, or http to the loopback interface for
+// native apps and local development, without a fragment.
+func validRedirectURI(redirectURI string) bool {
+	if len(redirectURI) > maxRedirectURILength || strings.Contains(redirectURI, "#") {
+		return false
+	}
+
+	parsed, err := url.Parse(redirectURI)
+	if err != nil || parsed.Host == "" || parsed.User != nil {
+		return false
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := parsed.Hostname()
+		if host == "localhost" {
+			return true
+		}
+
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	}
+
+	return false
+}
+
+func (client OAuthClient) AllowsRedirect(redirectURI string) bool {
+	for _, registered := range client.RedirectURIs {
+		if registered == redirectURI {
+			return true
+		}
+	}
+
+	return false
+}
+
+type OAuthAuthorization struct {
+	ResponseType        string   `json:"response_type,omitempty"`
+	ClientID            string   `json:"client_id,omitempty"`
+	ClientName          string   `json:"client_name,omitempty"`
+	RedirectURI         string   `json:"redirect_uri,omitempty"`
+	Scope               string   `json:"scope,omitempty"`
+	Scopes              []string `json:"scopes,omitempty"`
+	State               string   `json:"state,omitempty"`
+	CodeChallenge       string   `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string   `json:"code_challenge_method,omitempty"`
+	Approve             bool     `json:"approve,omitempty"`
+	ConsentRequired     bool     `json:"consent_required"`
+	RedirectTo          string   `json:"redirect_to,omitempty"`
+}
+
+type OAuthCode struct {
+	ID            uint64
+	ClientID      uint64
+	UserID        uint64
+	CodeHash      string
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+}
+
+type OAuthConsent struct {
+	ID         uint64    `json:"-"`
+	UserID     uint64    `json:"-"`
+	ClientID   uint64    `json:"-"`
+	Client     string    `json:"client_id,omitempty"`
+	ClientName string    `json:"client_name,omitempty"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// Covers tells whether the user already agreed to every scope requested.
+func (consent OAuthConsent) Covers(scopes []string) bool {
+	granted := make(map[string]bool, len(consent.Scopes))
+	for _, scope := range consent.Scopes {
+		granted[scope] = true
+	}
+
+	for _, scope := range scopes {
+		if !granted[scope] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type OAuthToken struct {
+	ID               uint64
+	ClientID         uint64
+	UserID           uint64
+	CodeID           uint64
+	AccessTokenHash  string
+	RefreshTokenHash string
+	Scopes           []string
+	ExpiresAt        time.Time
+	RefreshExpiresAt time.Time
+	CreatedAt        time.Time
+	Client           string
+}
+
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+type OAuthError struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+type OIDCLogin struct {
+	ID               uint64    `json:"-"`
+	StateHash        string    `json:"-"`
+	Nonce            string    `json:"-"`
+	CodeVerifier     string    `json:"-"`
+	ExpiresAt        time.Time `json:"-"`
+	State            string    `json:"state,omitempty"`
+	AuthorizationURL string    `json:"authorization_url,omitempty"`
+}
+
+type OIDCCallback struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+type UserIdentity struct {
+	ID        uint64    `json:"id,omitempty"`
+	UserID    uint64    `json:"userid,omitempty"`
+	Issuer    string    `json:"issuer,omitempty"`
+	Subject   string    `json:"subject,omitempty"`
+	CreatedAt time.Time `json:"createdat,omitempty"`
+}
@@ -3,4 +3,5 @@ package models
 type Password struct {
 	New     string `json:"new"`
 	Current string `json:"current"`
+	Token   string `json:"token,omitempty"`
 }
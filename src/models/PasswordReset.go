@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type PasswordReset struct {
+	ID        uint64    `json:"id,omitempty"`
+	UserID    uint64    `json:"userid,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	TokenHash string    `json:"-"`
+	ExpiresAt time.Time `json:"expiresat,omitempty"`
+	CreatedAt time.Time `json:"createdat,omitempty"`
+}
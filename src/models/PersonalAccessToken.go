@@ -0,0 +1,51 @@
+package models
+
+import (
+	"api/src/domain"
+	"strings"
+	"time"
+)
+
+const maxTokenNameLength = 50
+
+type PersonalAccessToken struct {
+	ID         uint64     `json:"id,omitempty"`
+	UserID     uint64     `json:"userid,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Token      string     `json:"token,omitempty"`
+	TokenHash  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresat,omitempty"`
+	LastUsedAt *time.Time `json:"lastusedat,omitempty"`
+	CreatedAt  time.Time  `json:"createdat,omitempty"`
+}
+
+func (token *PersonalAccessToken) Prepare(validScope func(string) bool) error {
+	token.Name = strings.TrimSpace(token.Name)
+	return token.validate(validScope)
+}
+
+func (token *PersonalAccessToken) validate(validScope func(string) bool) error {
+	var violations []domain.FieldError
+
+	if token.Name == "" {
+		violations = append(violations, domain.Field("name", "validation.name_required"))
+	} else if len([]rune(token.Name)) > maxTokenNameLength {
+		violations = append(violations, domain.Field("name", "validation.name_too_long", maxTokenNameLength))
+	}
+
+	if len(token.Scopes) == 0 {
+		violations = append(violations, domain.Field("scopes", "validation.scopes_required"))
+	}
+	for _, scope := range token.Scopes {
+		if !validScope(scope) {
+			violations = append(violations, domain.Field("scopes", "validation.scope_invalid", scope))
+		}
+	}
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		violations = append(violations, domain.Field("expiresat", "validation.expiration_past"))
+	}
+
+	return domain.InvalidFields(violations)
+}
@@ -1,7 +1,7 @@
 package models
 
 import (
-	"errors"
+	"api/src/domain"
 	"strings"
 	"time"
 )
@@ -13,6 +13,8 @@ type Publication struct {
 	AuthorID   uint64    `json:"authorid,omitempty"`
 	AuthorNick string    `json:"authornick,omitempty"`
 	Likes      uint64    `json:"likes"`
+	LikedByMe  bool      `json:"liked_by_me"`
+	Comments   uint64    `json:"comments"`
 	CreatedAt  time.Time `json:"createdat,omitempty"`
 }
 
@@ -26,14 +28,16 @@ func (publication *Publication) Prepare() error {
 }
 
 func (publication *Publication) validate() error {
+	var violations []domain.FieldError
+
 	if publication.Title == "" {
-		return errors.New("o título é obrigatório")
+		violations = append(violations, domain.Field("title", "validation.title_required"))
 	}
 	if publication.Content == "" {
-		return errors.New("o content é obrigatório")
+		violations = append(violations, domain.Field("content", "validation.content_required"))
 	}
 
-	return nil
+	return domain.InvalidFields(violations)
 }
 
 func (publication *Publication) format() {
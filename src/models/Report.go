@@ -0,0 +1,126 @@
+package models
+
+import (
+	"api/src/domain"
+	"strings"
+	"time"
+)
+
+const maxReportTextLength = 500
+
+const (
+	ReportPublication = "publication"
+	ReportUser        = "user"
+)
+
+const (
+	ReportOpen      = "open"
+	ReportActioned  = "actioned"
+	ReportDismissed = "dismissed"
+)
+
+const (
+	ActionHide    = "hide"
+	ActionDelete  = "delete"
+	ActionSuspend = "suspend"
+	ActionDismiss = "dismiss"
+)
+
+var reportReasons = []string{"spam", "harassment", "hate_speech", "violence", "nudity", "misinformation", "other"}
+
+type Report struct {
+	ID            uint64 `json:"id,omitempty"`
+	Kind          string `json:"kind,omitempty"`
+	ReporterID    uint64 `json:"reporterid,omitempty"`
+	UserID        uint64 `json:"userid,omitempty"`
+	PublicationID uint64 `json:"publicationid,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	Details       string `json:"details,omitempty"`
+	Status        string `json:"status,omitempty"`
+
+	// Snapshot of the reported account and publication when the report was made.
+	UserNick           string `json:"usernick,omitempty"`
+	PublicationTitle   string `json:"publicationtitle,omitempty"`
+	PublicationContent string `json:"publicationcontent,omitempty"`
+
+	DecidedAt *time.Time     `json:"decidedat,omitempty"`
+	CreatedAt time.Time      `json:"createdat,omitempty"`
+	Actions   []ReportAction `json:"actions,omitempty"`
+}
+
+// ReportAction is the audit trail of a report: each decision a moderator took
+// on it.
+type ReportAction struct {
+	ID            uint64    `json:"id,omitempty"`
+	ReportID      uint64    `json:"reportid,omitempty"`
+	ModeratorID   uint64    `json:"moderatorid,omitempty"`
+	ModeratorNick string    `json:"moderatornick,omitempty"`
+	Action        string    `json:"action,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"createdat,omitempty"`
+}
+
+func (report *Report) Prepare() error {
+	report.Details = strings.TrimSpace(report.Details)
+
+	var violations []domain.FieldError
+
+	if report.Reason == "" {
+		violations = append(violations, domain.Field("reason", "validation.reason_required"))
+	} else if !contains(reportReasons, report.Reason) {
+		violations = append(violations, domain.Field("reason", "validation.reason_invalid", strings.Join(reportReasons, ", ")))
+	}
+
+	if len([]rune(report.Details)) > maxReportTextLength {
+		violations = append(violations, domain.Field("details", "validation.details_too_long", maxReportTextLength))
+	}
+
+	return domain.InvalidFields(violations)
+}
+
+// Prepare checks the action against the kind of report it decides, as only
+// publications can be hidden or deleted.
+func (action *ReportAction) Prepare(kind string) error {
+	action.Note = strings.TrimSpace(action.Note)
+
+	var violations []domain.FieldError
+
+	switch action.Action {
+	case ActionSuspend, ActionDismiss:
+	case ActionHide, ActionDelete:
+		if kind != ReportPublication {
+			violations = append(violations, domain.Field("action", "validation.action_invalid"))
+		}
+	default:
+		violations = append(violations, domain.Field("action", "validation.action_invalid"))
+	}
+
+	if len([]rune(action.Note)) > maxReportTextLength {
+		violations = append(violations, domain.Field("note", "validation.note_too_long", maxReportTextLength))
+	}
+
+	return domain.InvalidFields(violations)
+}
+
+// Status is the state a report is left in once the action is taken.
+func (action ReportAction) Status() string {
+	if action.Action == ActionDismiss {
+		return ReportDismissed
+	}
+
+	return ReportActioned
+}
+
+func ValidReportStatus(status string) bool {
+	return contains([]string{ReportOpen, ReportActioned, ReportDismissed}, status)
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID               uint64    `json:"id,omitempty"`
+	UserID           uint64    `json:"userid,omitempty"`
+	RefreshTokenHash string    `json:"-"`
+	TokenID          string    `json:"-"`
+	UserAgent        string    `json:"useragent"`
+	IP               string    `json:"ip"`
+	Current          bool      `json:"current"`
+	ExpiresAt        time.Time `json:"expiresat,omitempty"`
+	LastSeenAt       time.Time `json:"lastseenat,omitempty"`
+	CreatedAt        time.Time `json:"createdat,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type SigningKey struct {
+	ID         string    `json:"id,omitempty"`
+	Algorithm  string    `json:"algorithm,omitempty"`
+	PrivateKey string    `json:"-"`
+	CreatedAt  time.Time `json:"createdat,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type TwoFactor struct {
+	Secret   string `json:"secret,omitempty"`
+	URI      string `json:"uri,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	LastStep int64  `json:"-"`
+}
+
+type TwoFactorCode struct {
+	ChallengeToken string `json:"challenge_token,omitempty"`
+	Code           string `json:"code,omitempty"`
+	RecoveryCode   string `json:"recovery_code,omitempty"`
+	Password       string `json:"password,omitempty"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+type LoginChallenge struct {
+	ID                uint64    `json:"-"`
+	UserID            uint64    `json:"-"`
+	TokenHash         string    `json:"-"`
+	Attempts          int       `json:"-"`
+	ExpiresAt         time.Time `json:"-"`
+	Token             string    `json:"challenge_token,omitempty"`
+	ExpiresIn         int64     `json:"expires_in,omitempty"`
+	TwoFactorRequired bool      `json:"two_factor_required"`
+}
@@ -1,8 +1,8 @@
 package models
 
 import (
+	"api/src/domain"
 	"api/src/security"
-	"errors"
 	"strings"
 	"time"
 
@@ -10,14 +10,46 @@ import (
 )
 
 type User struct {
+	ID            uint64     `json:"id,omitempty"`
+	Name          string     `json:"name,omitempty"`
+	Nick          string     `json:"nick,omitempty"`
+	Email         string     `json:"email,omitempty"`
+	Password      string     `json:"password,omitempty"`
+	EmailVerified bool       `json:"emailverified"`
+	Private       bool       `json:"private"`
+	Role          string     `json:"role,omitempty"`
+	SuspendedAt   *time.Time `json:"suspendedat,omitempty"`
+	CreatedAt     time.Time  `json:"createdat,omitempty"`
+}
+
+// Profile is the part of an account other users can see.
+type Profile struct {
 	ID        uint64    `json:"id,omitempty"`
 	Name      string    `json:"name,omitempty"`
 	Nick      string    `json:"nick,omitempty"`
-	Email     string    `json:"email,omitempty"`
-	Password  string    `json:"password,omitempty"`
+	Private   bool      `json:"private"`
 	CreatedAt time.Time `json:"createdat,omitempty"`
 }
 
+func (user User) Profile() Profile {
+	return Profile{
+		ID:        user.ID,
+		Name:      user.Name,
+		Nick:      user.Nick,
+		Private:   user.Private,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func Profiles(users []User) []Profile {
+	profiles := make([]Profile, 0, len(users))
+	for _, user := range users {
+		profiles = append(profiles, user.Profile())
+	}
+
+	return profiles
+}
+
 func (user *User) Prepare(stage string) error {
 	if err := user.validate(stage); err != nil {
 		return err
@@ -35,6 +67,11 @@ func (user *User) format(stage string) error {
 	user.Nick = strings.TrimSpace(user.Nick)
 	user.Email = strings.TrimSpace(user.Email)
 
+	// Verification, role and suspension are never taken from the request body.
+	user.EmailVerified = false
+	user.Role = ""
+	user.SuspendedAt = nil
+
 	if stage == "register" {
 		passwordWithHash, err := security.HashPassword(user.Password)
 		if err != nil {
@@ -48,23 +85,24 @@ func (user *User) format(stage string) error {
 }
 
 func (user *User) validate(stage string) error {
+	var violations []domain.FieldError
+
 	if user.Name == "" {
-		return errors.New("the name is mandatory and cannot be blank")
+		violations = append(violations, domain.Field("name", "validation.name_required"))
 	}
 	if user.Nick == "" {
-		return errors.New("the nick is mandatory and cannot be blank")
-	}
-	if user.Email == "" {
-		return errors.New("the e-mail is mandatory and cannot be blank")
+		violations = append(violations, domain.Field("nick", "validation.nick_required"))
 	}
 
-	if err := checkmail.ValidateFormat(user.Email); err != nil {
-		return errors.New("the email provided does not correspond to a valid address")
+	if user.Email == "" {
+		violations = append(violations, domain.Field("email", "validation.email_required"))
+	} else if err := checkmail.ValidateFormat(user.Email); err != nil {
+		violations = append(violations, domain.Field("email", "validation.email_invalid"))
 	}
 
 	if stage == "register" && user.Password == "" {
-		return errors.New("the password is mandatory and cannot be blank")
+		violations = append(violations, domain.Field("password", "validation.password_required"))
 	}
 
-	return nil
+	return domain.InvalidFields(violations)
 }
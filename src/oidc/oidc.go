@@ -0,0 +1,471 @@
+// Package oidc signs users in with an external OpenID Connect provider using
+// the authorization code flow with PKCE.
+package oidc
+
+import (
+	"api/src/config"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	requestTimeout     = time.Second * 10
+	keyRefreshInterval = time.Minute
+	clockSkew          = time.Minute
+)
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// Identity is what the provider asserts about the user in the ID token.
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       string
+	client       *http.Client
+
+	mu            sync.Mutex
+	discovery     *discovery
+	keys          map[string]interface{}
+	keysFetchedAt time.Time
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// New configures the provider from OIDC_* settings, answering nil when no
+// issuer is configured. Discovery is lazy, so the API starts even while the
+// provider is unreachable.
+func New() *Provider {
+	if config.OIDCIssuer == "" {
+		return nil
+	}
+
+	return NewProvider(
+		config.OIDCIssuer,
+		config.OIDCClientID,
+		config.OIDCClientSecret,
+		config.OIDCRedirectURL,
+		config.OIDCScopes,
+		&http.Client{Timeout: requestTimeout},
+	)
+}
+
+func NewProvider(issuer, clientID, clientSecret, redirectURL, scopes string, client *http.Client) *Provider {
+	return &Provider{
+		issuer:       issuer,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+		client:       client,
+	}
+}
+
+// AuthorizationURL is where the browser must be sent to sign in at the provider.
+func (p *Provider) AuthorizationURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	document, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authorizationURL, err := url.Parse(document.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.clientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", p.scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+
+	return authorizationURL.String(), nil
+}
+
+// Exchange redeems the authorization code and answers the identity asserted
+// by the ID token, once its signature, issuer, audience, lifetime and nonce
+// are verified.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Identity, error) {
+	document, err := p.discover(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.clientID)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, document.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.clientSecret != "" {
+		request.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = p.do(request, &tokens); err != nil {
+		if tokens.Error != "" {
+			return Identity{}, fmt.Errorf("the provider refused the code: %s", strings.TrimSpace(tokens.Error+" "+tokens.ErrorDescription))
+		}
+		return Identity{}, err
+	}
+
+	if tokens.IDToken == "" {
+		return Identity{}, errors.New("the provider did not answer an ID token")
+	}
+
+	return p.verify(ctx, tokens.IDToken, nonce)
+}
+
+func (p *Provider) verify(ctx context.Context, rawIDToken, nonce string) (Identity, error) {
+	claims := idTokenClaims{issuer: p.issuer, clientID: p.clientID}
+	parser := jwt.Parser{ValidMethods: signingMethods}
+
+	_, err := parser.ParseWithClaims(rawIDToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return p.key(ctx, keyID)
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return Identity{}, errors.New("the ID token nonce does not match the sign-in")
+	}
+
+	return Identity{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var document discovery
+	if err = p.do(request, &document); err != nil {
+		return nil, err
+	}
+
+	if document.Issuer != p.issuer {
+		return nil, fmt.Errorf("the discovery document belongs to the issuer %q", document.Issuer)
+	}
+
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return nil, errors.New("the discovery document is missing endpoints")
+	}
+
+	p.discovery = &document
+	return p.discovery, nil
+}
+
+// key answers the provider key with the given ID, fetching the key set again
+// when it is unknown, since providers rotate keys without notice.
+func (p *Provider) key(ctx context.Context, keyID string) (interface{}, error) {
+	document, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(keyID); ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < keyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, document.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwkSet
+	if err = p.do(request, &set); err != nil {
+		return nil, err
+	}
+
+	p.keys = make(map[string]interface{}, len(set.Keys))
+	p.keysFetchedAt = time.Now()
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		if key, err := jwk.publicKey(); err == nil {
+			p.keys[jwk.KeyID] = key
+		}
+	}
+
+	if key, ok := p.lookup(keyID); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+// lookup accepts tokens without a key ID only when the set has a single key.
+func (p *Provider) lookup(keyID string) (interface{}, bool) {
+	if keyID == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[keyID]
+	return key, ok
+}
+
+func (p *Provider) do(request *http.Request, target interface{}) error {
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	decodeErr := json.Unmarshal(body, target)
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s answered %d", request.Method, request.URL, response.StatusCode)
+	}
+
+	return decodeErr
+}
+
+type idTokenClaims struct {
+	Nonce             string   `json:"nonce"`
+	Email             string   `json:"email"`
+	EmailVerified     boolean  `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Issuer            string   `json:"iss"`
+	Subject           string   `json:"sub"`
+	Audience          audience `json:"aud"`
+	AuthorizedParty   string   `json:"azp"`
+	ExpiresAt         int64    `json:"exp"`
+	IssuedAt          int64    `json:"iat"`
+
+	issuer   string
+	clientID string
+}
+
+func (claims idTokenClaims) Valid() error {
+	now := time.Now()
+
+	if claims.Issuer != claims.issuer {
+		return errors.New("the ID token was issued by another provider")
+	}
+
+	if claims.Subject == "" {
+		return errors.New("the ID token has no subject")
+	}
+
+	if !claims.Audience.contains(claims.clientID) {
+		return errors.New("the ID token is not intended for this client")
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != claims.clientID {
+		return errors.New("the ID token was authorized for another party")
+	}
+
+	if claims.ExpiresAt == 0 || now.Add(-clockSkew).Unix() > claims.ExpiresAt {
+		return errors.New("the ID token is expired")
+	}
+
+	if claims.IssuedAt > now.Add(clockSkew).Unix() {
+		return errors.New("the ID token was issued in the future")
+	}
+
+	return nil
+}
+
+// audience accepts both forms the aud claim can take: a string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+
+	*a = many
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, value := range a {
+		if value == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// boolean also accepts "true" and "false" strings, which some providers send
+// for email_verified.
+type boolean bool
+
+func (b *boolean) UnmarshalJSON(data []byte) error {
+	var value bool
+	if err := json.Unmarshal(data, &value); err == nil {
+		*b = boolean(value)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	*b = boolean(text == "true")
+	return nil
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	KeyType  string `json:"kty"`
+	KeyID    string `json:"kid"`
+	Use      string `json:"use"`
+	Modulus  string `json:"n"`
+	Exponent string `json:"e"`
+	Curve    string `json:"crv"`
+	X        string `json:"x"`
+	Y        string `json:"y"`
+}
+
+func (key jwk) publicKey() (interface{}, error) {
+	switch key.KeyType {
+	case "RSA":
+		modulus, err := decodeBigInt(key.Modulus)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent, err := decodeBigInt(key.Exponent)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch key.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", key.Curve)
+		}
+
+		x, err := decodeBigInt(key.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(key.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the key is not on its curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", key.KeyType)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
@@ -0,0 +1,258 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	testClientID     = "social-network"
+	testClientSecret = "secret"
+	testRedirectURL  = "https://api.example.com/login/oidc/callback"
+	testNonce        = "nonce-of-the-sign-in"
+	testVerifier     = "verifier-of-the-sign-in"
+)
+
+// testIdP is a minimal OpenID provider answering discovery, its key set and
+// the token endpoint, which hands out whatever ID token the test sets.
+type testIdP struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	idToken string
+	issuer  string
+	form    url.Values
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &testIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.issuer,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if clientID, clientSecret, ok := r.BasicAuth(); !ok || clientID != testClientID || clientSecret != testClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		r.ParseForm()
+		idp.form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idp.idToken})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	idp.issuer = idp.server.URL
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *testIdP) provider() *Provider {
+	return NewProvider(idp.server.URL, testClientID, testClientSecret, testRedirectURL, "openid email", idp.server.Client())
+}
+
+func (idp *testIdP) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func (idp *testIdP) claims(changes map[string]interface{}) jwt.MapClaims {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            idp.issuer,
+		"sub":            "subject-1",
+		"aud":            testClientID,
+		"exp":            now.Add(time.Minute * 5).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          testNonce,
+		"email":          "user@example.com",
+		"email_verified": true,
+		"name":           "User",
+	}
+
+	for claim, value := range changes {
+		if value == nil {
+			delete(claims, claim)
+			continue
+		}
+		claims[claim] = value
+	}
+
+	return claims
+}
+
+func TestExchangeVerifiesTheIDToken(t *testing.T) {
+	idp := newTestIdP(t)
+	now := time.Now()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signWith := func(method jwt.SigningMethod, key interface{}, keyID string, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = keyID
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name    string
+		idToken string
+		wantErr bool
+	}{
+		{"valid", idp.sign(t, idp.claims(nil)), false},
+		{"audience in an array", idp.sign(t, idp.claims(map[string]interface{}{"aud": []string{testClientID}})), false},
+		{"several audiences authorized for the client", idp.sign(t, idp.claims(map[string]interface{}{
+			"aud": []string{testClientID, "other"}, "azp": testClientID,
+		})), false},
+		{"email verified as text", idp.sign(t, idp.claims(map[string]interface{}{"email_verified": "true"})), false},
+		{"other nonce", idp.sign(t, idp.claims(map[string]interface{}{"nonce": "replayed"})), true},
+		{"missing nonce", idp.sign(t, idp.claims(map[string]interface{}{"nonce": nil})), true},
+		{"other issuer", idp.sign(t, idp.claims(map[string]interface{}{"iss": "https://evil.example.com"})), true},
+		{"other audience", idp.sign(t, idp.claims(map[string]interface{}{"aud": "other"})), true},
+		{"several audiences without authorized party", idp.sign(t, idp.claims(map[string]interface{}{
+			"aud": []string{testClientID, "other"},
+		})), true},
+		{"several audiences authorized for another party", idp.sign(t, idp.claims(map[string]interface{}{
+			"aud": []string{testClientID, "other"}, "azp": "other",
+		})), true},
+		{"missing subject", idp.sign(t, idp.claims(map[string]interface{}{"sub": nil})), true},
+		{"expired", idp.sign(t, idp.claims(map[string]interface{}{"exp": now.Add(-time.Minute * 5).Unix()})), true},
+		{"missing expiry", idp.sign(t, idp.claims(map[string]interface{}{"exp": nil})), true},
+		{"issued in the future", idp.sign(t, idp.claims(map[string]interface{}{"iat": now.Add(time.Minute * 5).Unix()})), true},
+		{"signed by another key", signWith(jwt.SigningMethodRS256, otherKey, "test-key", idp.claims(nil)), true},
+		{"unknown key", signWith(jwt.SigningMethodRS256, idp.key, "rotated-key", idp.claims(nil)), true},
+		{"symmetric algorithm", signWith(jwt.SigningMethodHS256, []byte(testClientSecret), "test-key", idp.claims(nil)), true},
+	}
+
+	provider := idp.provider()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idp.idToken = test.idToken
+
+			identity, err := provider.Exchange(context.Background(), "code", testVerifier, testNonce)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Exchange accepted the ID token: %+v", identity)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+
+			want := Identity{
+				Issuer:        idp.issuer,
+				Subject:       "subject-1",
+				Email:         "user@example.com",
+				EmailVerified: true,
+				Name:          "User",
+			}
+			if identity != want {
+				t.Errorf("Exchange = %+v, want %+v", identity, want)
+			}
+
+			for parameter, value := range map[string]string{
+				"grant_type":    "authorization_code",
+				"code":          "code",
+				"redirect_uri":  testRedirectURL,
+				"code_verifier": testVerifier,
+			} {
+				if got := idp.form.Get(parameter); got != value {
+					t.Errorf("token request %s = %q, want %q", parameter, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscoveryMustMatchTheIssuer(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.issuer = "https://evil.example.com"
+	idp.idToken = idp.sign(t, idp.claims(nil))
+
+	if _, err := idp.provider().Exchange(context.Background(), "code", testVerifier, testNonce); err == nil {
+		t.Fatal("Exchange trusted a discovery document of another issuer")
+	}
+}
+
+func TestAuthorizationURL(t *testing.T) {
+	idp := newTestIdP(t)
+
+	value, err := idp.provider().AuthorizationURL(context.Background(), "state", testNonce, "challenge")
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+
+	authorizationURL, err := url.Parse(value)
+	if err != nil {
+		t.Fatalf("AuthorizationURL answered %q: %v", value, err)
+	}
+
+	if got := authorizationURL.Scheme + "://" + authorizationURL.Host + authorizationURL.Path; got != idp.server.URL+"/authorize" {
+		t.Errorf("AuthorizationURL points to %s, want the discovered endpoint", got)
+	}
+
+	for parameter, want := range map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"scope":                 "openid email",
+		"state":                 "state",
+		"nonce":                 testNonce,
+		"code_challenge":        "challenge",
+		"code_challenge_method": "S256",
+	} {
+		if got := authorizationURL.Query().Get(parameter); got != want {
+			t.Errorf("%s = %q, want %q", parameter, got, want)
+		}
+	}
+}
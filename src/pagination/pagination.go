@@ -0,0 +1,192 @@
+package pagination
+
+import (
+	"api/src/domain"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uint64
+}
+
+type Params struct {
+	Limit     int
+	Cursor    *Cursor
+	Backward  bool
+	Ascending bool
+}
+
+type Page struct {
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	PrevCursor string      `json:"prev_cursor,omitempty"`
+}
+
+func (cursor Cursor) Encode() string {
+	value := fmt.Sprintf("%d:%d", cursor.CreatedAt.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func Decode(value string) (Cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, domain.Validation("pagination.invalid_cursor")
+	}
+
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 2 {
+		return Cursor{}, domain.Validation("pagination.invalid_cursor")
+	}
+
+	createdAt, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, domain.Validation("pagination.invalid_cursor")
+	}
+
+	ID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, domain.Validation("pagination.invalid_cursor")
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, createdAt), ID: ID}, nil
+}
+
+func FromRequest(r *http.Request) (Params, error) {
+	query := r.URL.Query()
+	params := Params{Limit: DefaultLimit}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Params{}, domain.Validation("pagination.invalid_limit", MaxLimit)
+		}
+		params.Limit = limit
+	}
+
+	after, before := query.Get("after"), query.Get("before")
+	if after != "" && before != "" {
+		return Params{}, domain.Validation("pagination.conflicting_cursors")
+	}
+
+	value := after
+	if before != "" {
+		value = before
+		params.Backward = true
+	}
+
+	if value != "" {
+		cursor, err := Decode(value)
+		if err != nil {
+			return Params{}, err
+		}
+		params.Cursor = &cursor
+	}
+
+	return params, nil
+}
+
+// Where and OrderBy walk the list away from the cursor: towards older rows when
+// reading a newest-first list forwards, towards newer rows when going backwards.
+func (params Params) Where(createdAtColumn, IDColumn string) (string, []interface{}) {
+	if params.Cursor == nil {
+		return "1 = 1", nil
+	}
+
+	operator := ">"
+	if params.descending() {
+		operator = "<"
+	}
+
+	condition := fmt.Sprintf("(%s %s ? or (%s = ? and %s %s ?))",
+		createdAtColumn, operator, createdAtColumn, IDColumn, operator,
+	)
+
+	return condition, []interface{}{params.Cursor.CreatedAt, params.Cursor.CreatedAt, params.Cursor.ID}
+}
+
+func (params Params) OrderBy(createdAtColumn, IDColumn string) string {
+	direction := "asc"
+	if params.descending() {
+		direction = "desc"
+	}
+
+	return fmt.Sprintf("order by %s %s, %s %s limit %d",
+		createdAtColumn, direction, IDColumn, direction, params.Limit+1,
+	)
+}
+
+func (params Params) Page(items interface{}, key func(item interface{}) Cursor) Page {
+	values := reflect.ValueOf(items)
+	if values.IsNil() {
+		values = reflect.MakeSlice(values.Type(), 0, 0)
+	}
+
+	hasMore := values.Len() > params.Limit
+	if hasMore {
+		values = values.Slice(0, params.Limit)
+	}
+
+	if params.Backward {
+		swap := reflect.Swapper(values.Interface())
+		for i, j := 0, values.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	page := Page{Data: values.Interface()}
+	if values.Len() == 0 {
+		return page
+	}
+
+	first := key(values.Index(0).Interface())
+	last := key(values.Index(values.Len() - 1).Interface())
+
+	if (params.Backward && params.Cursor != nil) || (!params.Backward && hasMore) {
+		page.NextCursor = last.Encode()
+	}
+
+	if (params.Backward && hasMore) || (!params.Backward && params.Cursor != nil) {
+		page.PrevCursor = first.Encode()
+	}
+
+	return page
+}
+
+func (page Page) Links(r *http.Request) string {
+	var links []string
+
+	if page.NextCursor != "" {
+		links = append(links, link(r, "after", page.NextCursor, "next"))
+	}
+
+	if page.PrevCursor != "" {
+		links = append(links, link(r, "before", page.PrevCursor, "prev"))
+	}
+
+	return strings.Join(links, ", ")
+}
+
+func (params Params) descending() bool {
+	return params.Ascending == params.Backward
+}
+
+func link(r *http.Request, parameter, cursor, relation string) string {
+	query := r.URL.Query()
+	query.Del("after")
+	query.Del("before")
+	query.Set(parameter, cursor)
+
+	return fmt.Sprintf("<%s?%s>; rel=\"%s\"", r.URL.Path, query.Encode(), relation)
+}
@@ -0,0 +1,193 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"zero", Cursor{CreatedAt: time.Unix(0, 0), ID: 0}},
+		{"nanoseconds", Cursor{CreatedAt: time.Date(2021, 3, 4, 5, 6, 7, 891011, time.UTC), ID: 42}},
+		{"largest id", Cursor{CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), ID: ^uint64(0)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := Decode(test.cursor.Encode())
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			if !decoded.CreatedAt.Equal(test.cursor.CreatedAt) || decoded.ID != test.cursor.ID {
+				t.Errorf("Decode(Encode(%v)) = %v", test.cursor, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsInvalidCursors(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("1:23"))},
+		{"missing id", encode("1614834367000000000")},
+		{"extra part", encode("1:2:3")},
+		{"text time", encode("yesterday:2")},
+		{"negative id", encode("1:-2")},
+		{"empty", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if cursor, err := Decode(test.value); err == nil {
+				t.Errorf("Decode(%q) = %v, want an error", test.value, cursor)
+			}
+		})
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	cursor := Cursor{CreatedAt: time.Unix(100, 0), ID: 7}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    Params
+		wantErr bool
+	}{
+		{"defaults", "", Params{Limit: DefaultLimit}, false},
+		{"limit", "limit=5", Params{Limit: 5}, false},
+		{"after", "after=" + cursor.Encode(), Params{Limit: DefaultLimit, Cursor: &cursor}, false},
+		{"before", "limit=3&before=" + cursor.Encode(), Params{Limit: 3, Cursor: &cursor, Backward: true}, false},
+		{"zero limit", "limit=0", Params{}, true},
+		{"limit above maximum", "limit=101", Params{}, true},
+		{"text limit", "limit=ten", Params{}, true},
+		{"both cursors", "after=" + cursor.Encode() + "&before=" + cursor.Encode(), Params{}, true},
+		{"invalid cursor", "after=invalid", Params{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			params, err := FromRequest(httptest.NewRequest("GET", "/publications?"+test.query, nil))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("FromRequest(%q) = %+v, want an error", test.query, params)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FromRequest(%q): %v", test.query, err)
+			}
+
+			if params.Limit != test.want.Limit || params.Backward != test.want.Backward {
+				t.Errorf("FromRequest(%q) = %+v, want %+v", test.query, params, test.want)
+			}
+
+			if (params.Cursor == nil) != (test.want.Cursor == nil) ||
+				(params.Cursor != nil && (!params.Cursor.CreatedAt.Equal(test.want.Cursor.CreatedAt) || params.Cursor.ID != test.want.Cursor.ID)) {
+				t.Errorf("FromRequest(%q) cursor = %v, want %v", test.query, params.Cursor, test.want.Cursor)
+			}
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	key := func(item interface{}) Cursor {
+		return Cursor{CreatedAt: time.Unix(int64(item.(int)), 0), ID: uint64(item.(int))}
+	}
+	cursor := &Cursor{CreatedAt: time.Unix(50, 0), ID: 50}
+
+	tests := []struct {
+		name     string
+		params   Params
+		items    []int
+		wantData []int
+		wantNext *int
+		wantPrev *int
+	}{
+		{"empty", Params{Limit: 2}, nil, []int{}, nil, nil},
+		{"first page with more", Params{Limit: 2}, []int{9, 8, 7}, []int{9, 8}, intPointer(8), nil},
+		{"last page", Params{Limit: 2, Cursor: cursor}, []int{6}, []int{6}, nil, intPointer(6)},
+		{"middle page", Params{Limit: 2, Cursor: cursor}, []int{6, 5, 4}, []int{6, 5}, intPointer(5), intPointer(6)},
+		{"backward with more", Params{Limit: 2, Cursor: cursor, Backward: true}, []int{51, 52, 53}, []int{52, 51}, intPointer(51), intPointer(52)},
+		{"backward to the start", Params{Limit: 2, Cursor: cursor, Backward: true}, []int{51}, []int{51}, intPointer(51), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := test.params.Page(test.items, key)
+
+			if !reflect.DeepEqual(page.Data, test.wantData) {
+				t.Errorf("data = %v, want %v", page.Data, test.wantData)
+			}
+
+			if want := encoded(key, test.wantNext); page.NextCursor != want {
+				t.Errorf("next cursor = %q, want %q", page.NextCursor, want)
+			}
+
+			if want := encoded(key, test.wantPrev); page.PrevCursor != want {
+				t.Errorf("prev cursor = %q, want %q", page.PrevCursor, want)
+			}
+		})
+	}
+}
+
+func TestWhereAndOrderBy(t *testing.T) {
+	cursor := &Cursor{CreatedAt: time.Unix(50, 0), ID: 50}
+
+	tests := []struct {
+		name      string
+		params    Params
+		wantWhere string
+		wantOrder string
+	}{
+		{"no cursor", Params{Limit: 20}, "1 = 1", "order by p.createdat desc, p.id desc limit 21"},
+		{"forwards", Params{Limit: 20, Cursor: cursor}, "(p.createdat < ? or (p.createdat = ? and p.id < ?))", "order by p.createdat desc, p.id desc limit 21"},
+		{"backwards", Params{Limit: 20, Cursor: cursor, Backward: true}, "(p.createdat > ? or (p.createdat = ? and p.id > ?))", "order by p.createdat asc, p.id asc limit 21"},
+		{"ascending forwards", Params{Limit: 5, Cursor: cursor, Ascending: true}, "(p.createdat > ? or (p.createdat = ? and p.id > ?))", "order by p.createdat asc, p.id asc limit 6"},
+		{"ascending backwards", Params{Limit: 5, Cursor: cursor, Ascending: true, Backward: true}, "(p.createdat < ? or (p.createdat = ? and p.id < ?))", "order by p.createdat desc, p.id desc limit 6"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			where, args := test.params.Where("p.createdat", "p.id")
+			if where != test.wantWhere {
+				t.Errorf("Where = %q, want %q", where, test.wantWhere)
+			}
+
+			if test.params.Cursor != nil && len(args) != 3 {
+				t.Errorf("Where args = %v, want the cursor time twice and its id", args)
+			}
+
+			if order := test.params.OrderBy("p.createdat", "p.id"); order != test.wantOrder {
+				t.Errorf("OrderBy = %q, want %q", order, test.wantOrder)
+			}
+		})
+	}
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func encoded(key func(item interface{}) Cursor, item *int) string {
+	if item == nil {
+		return ""
+	}
+
+	return key(*item).Encode()
+}
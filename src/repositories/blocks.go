@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+)
+
+// notBlocked excludes the rows whose user, in column, blocked the viewer or was
+// blocked by them. It takes the viewer twice.
+func notBlocked(column string) string {
+	return `not exists(
+		select 1 from blocks b
+		where (b.blocker_id = ` + column + ` and b.blocked_id = ?) or (b.blocker_id = ? and b.blocked_id = ` + column + `)
+	)`
+}
+
+type Blocks interface {
+	Block(ctx context.Context, blockerID, blockedID uint64) error
+	Unblock(ctx context.Context, blockerID, blockedID uint64) error
+	SearchBlocked(ctx context.Context, blockerID uint64, params pagination.Params) ([]models.User, error)
+	Blocked(ctx context.Context, userID, otherID uint64) (bool, error)
+}
+
+type blocks struct {
+	db *sql.DB
+}
+
+func NewRepositoryBlocks(db *sql.DB) Blocks {
+	return &blocks{db}
+}
+
+// Block also removes the follows and follow requests between both users, in
+// both directions.
+func (b blocks) Block(ctx context.Context, blockerID, blockedID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "insert ignore into blocks (blocker_id, blocked_id) values (?, ?)", blockerID, blockedID); err != nil {
+		return translate(err)
+	}
+
+	for _, table := range []string{"followers", "follow_requests"} {
+		if _, err = tx.ExecContext(ctx, `
+			delete from `+table+`
+			where (user_id = ? and follower_id = ?) or (user_id = ? and follower_id = ?)
+		`, blockerID, blockedID, blockedID, blockerID); err != nil {
+			return translate(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (b blocks) Unblock(ctx context.Context, blockerID, blockedID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := b.db.PrepareContext(ctx, "delete from blocks where blocker_id = ? and blocked_id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, blockerID, blockedID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (b blocks) SearchBlocked(ctx context.Context, blockerID uint64, params pagination.Params) ([]models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("u.createdat", "u.id")
+
+	rows, err := b.db.QueryContext(ctx, `
+		select u.id, u.name, u.nick, u.email, u.email_verified, u.createdat
+		from users u
+		inner join blocks b on u.id = b.blocked_id
+		where b.blocker_id = ? and `+condition+" "+params.OrderBy("u.createdat", "u.id"),
+		append([]interface{}{blockerID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRelatedUsers(rows)
+}
+
+// Blocked tells whether either user blocked the other.
+func (b blocks) Blocked(ctx context.Context, userID, otherID uint64) (bool, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := b.db.QueryContext(ctx, `
+		select 1 from blocks
+		where (blocker_id = ? and blocked_id = ?) or (blocker_id = ? and blocked_id = ?)
+	`, userID, otherID, otherID, userID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+
+	return true, nil
+}
+
+func scanRelatedUsers(rows *sql.Rows) ([]models.User, error) {
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Nick,
+			&user.Email,
+			&user.EmailVerified,
+			&user.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
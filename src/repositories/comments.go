@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+)
+
+const selectComments = `
+	select c.id, c.publication_id, coalesce(c.parent_id, 0), c.depth, c.author_id, u.nick, c.content,
+	(select count(*) from comments r where r.parent_id = c.id) as replies,
+	c.createdat, c.updatedat
+	from comments c
+	inner join users u on u.id = c.author_id
+`
+
+type Comments interface {
+	Create(ctx context.Context, comment models.Comment) (uint64, error)
+	SearchID(ctx context.Context, commentID uint64) (models.Comment, error)
+	SearchPublication(ctx context.Context, publicationID, parentID, viewerID uint64, params pagination.Params) ([]models.Comment, error)
+	Update(ctx context.Context, commentID uint64, comment models.Comment) error
+	Delete(ctx context.Context, commentID uint64) error
+}
+
+type comments struct {
+	db *sql.DB
+}
+
+func NewRepositoryComments(db *sql.DB) Comments {
+	return &comments{db}
+}
+
+func (c comments) Create(ctx context.Context, comment models.Comment) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := c.db.PrepareContext(ctx, "insert into comments (publication_id, parent_id, depth, author_id, content) values (?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	var parentID sql.NullInt64
+	if comment.ParentID != 0 {
+		parentID = sql.NullInt64{Int64: int64(comment.ParentID), Valid: true}
+	}
+
+	result, err := statement.ExecContext(ctx, comment.PublicationID, parentID, comment.Depth, comment.AuthorID, comment.Content)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (c comments) SearchID(ctx context.Context, commentID uint64) (models.Comment, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := c.db.QueryContext(ctx, selectComments+"where c.id = ?", commentID)
+	if err != nil {
+		return models.Comment{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.Comment{}, noRows(rows, "comment.not_found")
+	}
+
+	return scanComment(rows)
+}
+
+func (c comments) SearchPublication(ctx context.Context, publicationID, parentID, viewerID uint64, params pagination.Params) ([]models.Comment, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("c.createdat", "c.id")
+
+	rows, err := c.db.QueryContext(ctx, selectComments+`
+		where c.publication_id = ? and coalesce(c.parent_id, 0) = ? and `+notBlocked("c.author_id")+" and "+
+		condition+" "+params.OrderBy("c.createdat", "c.id"),
+		append([]interface{}{publicationID, parentID, viewerID, viewerID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+func (c comments) Update(ctx context.Context, commentID uint64, comment models.Comment) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := c.db.PrepareContext(ctx, "update comments set content = ? where id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, comment.Content, commentID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (c comments) Delete(ctx context.Context, commentID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := c.db.PrepareContext(ctx, "delete from comments where id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, commentID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func scanComment(rows *sql.Rows) (models.Comment, error) {
+	var comment models.Comment
+	err := rows.Scan(
+		&comment.ID, &comment.PublicationID, &comment.ParentID, &comment.Depth, &comment.AuthorID, &comment.AuthorNick,
+		&comment.Content, &comment.Replies, &comment.CreatedAt, &comment.UpdatedAt,
+	)
+
+	return comment, err
+}
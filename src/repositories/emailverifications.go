@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+)
+
+type EmailVerifications interface {
+	Create(ctx context.Context, verification models.EmailVerification) (uint64, error)
+	Consume(ctx context.Context, tokenHash string) (models.EmailVerification, error)
+	RevokeUser(ctx context.Context, userID uint64) error
+}
+
+type emailVerifications struct {
+	db *sql.DB
+}
+
+func NewRepositoryEmailVerifications(db *sql.DB) EmailVerifications {
+	return &emailVerifications{db}
+}
+
+func (e emailVerifications) Create(ctx context.Context, verification models.EmailVerification) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := e.db.PrepareContext(ctx, "insert into email_verifications (user_id, email, token, expires_at) values (?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, verification.UserID, verification.Email, verification.TokenHash, verification.ExpiresAt)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (e emailVerifications) Consume(ctx context.Context, tokenHash string) (models.EmailVerification, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.EmailVerification{}, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		select id, user_id, email, expires_at, createdat from email_verifications
+		where token = ? and used_at is null and expires_at > now()
+		for update
+	`, tokenHash)
+	if err != nil {
+		return models.EmailVerification{}, err
+	}
+
+	if !rows.Next() {
+		err = noRows(rows, "email_verification.invalid_token")
+		rows.Close()
+		return models.EmailVerification{}, err
+	}
+
+	var verification models.EmailVerification
+	err = rows.Scan(
+		&verification.ID,
+		&verification.UserID,
+		&verification.Email,
+		&verification.ExpiresAt,
+		&verification.CreatedAt,
+	)
+	rows.Close()
+	if err != nil {
+		return models.EmailVerification{}, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "update email_verifications set used_at = now() where id = ?", verification.ID); err != nil {
+		return models.EmailVerification{}, translate(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.EmailVerification{}, err
+	}
+
+	return verification, nil
+}
+
+func (e emailVerifications) RevokeUser(ctx context.Context, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := e.db.PrepareContext(ctx, "update email_verifications set used_at = now() where user_id = ? and used_at is null")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, userID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
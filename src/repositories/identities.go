@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+)
+
+type UserIdentities interface {
+	Create(ctx context.Context, identity models.UserIdentity) (uint64, error)
+	SearchSubject(ctx context.Context, issuer, subject string) (models.UserIdentity, error)
+}
+
+type userIdentities struct {
+	db *sql.DB
+}
+
+func NewRepositoryUserIdentities(db *sql.DB) UserIdentities {
+	return &userIdentities{db}
+}
+
+func (u userIdentities) Create(ctx context.Context, identity models.UserIdentity) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "insert into user_identities (user_id, issuer, subject) values (?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, identity.UserID, identity.Issuer, identity.Subject)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (u userIdentities) SearchSubject(ctx context.Context, issuer, subject string) (models.UserIdentity, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := u.db.QueryContext(ctx,
+		"select id, user_id, issuer, subject, createdat from user_identities where issuer = ? and subject = ?",
+		issuer, subject,
+	)
+	if err != nil {
+		return models.UserIdentity{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.UserIdentity{}, noRows(rows, "user.not_found")
+	}
+
+	var identity models.UserIdentity
+	if err = rows.Scan(&identity.ID, &identity.UserID, &identity.Issuer, &identity.Subject, &identity.CreatedAt); err != nil {
+		return models.UserIdentity{}, err
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	signingKeysLockName    = "signing_keys"
+	signingKeysLockTimeout = 10 * time.Second
+)
+
+type SigningKeys interface {
+	Create(ctx context.Context, key models.SigningKey) error
+	Search(ctx context.Context) ([]models.SigningKey, error)
+	Delete(ctx context.Context, ID string) error
+	Lock(ctx context.Context, function func() error) error
+}
+
+type signingKeys struct {
+	db *sql.DB
+}
+
+func NewRepositorySigningKeys(db *sql.DB) SigningKeys {
+	return &signingKeys{db}
+}
+
+func (k signingKeys) Create(ctx context.Context, key models.SigningKey) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := k.db.PrepareContext(ctx, "insert into signing_keys (id, algorithm, private_key, createdat) values (?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, key.ID, key.Algorithm, key.PrivateKey, key.CreatedAt); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (k signingKeys) Search(ctx context.Context) ([]models.SigningKey, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := k.db.QueryContext(ctx, "select id, algorithm, private_key, createdat from signing_keys order by createdat desc")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+	for rows.Next() {
+		var key models.SigningKey
+		if err = rows.Scan(
+			&key.ID,
+			&key.Algorithm,
+			&key.PrivateKey,
+			&key.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (k signingKeys) Delete(ctx context.Context, ID string) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := k.db.PrepareContext(ctx, "delete from signing_keys where id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+// Lock runs the function while holding a database lock, so only one instance at
+// a time rotates the keys.
+func (k signingKeys) Lock(ctx context.Context, function func() error) error {
+	conn, err := k.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// GET_LOCK is bound to the connection, which stays reserved until the lock
+	// is released.
+	var locked sql.NullInt64
+	if err = conn.QueryRowContext(ctx, "select get_lock(?, ?)", signingKeysLockName, int(signingKeysLockTimeout.Seconds())).Scan(&locked); err != nil {
+		return err
+	}
+
+	if !locked.Valid || locked.Int64 != 1 {
+		return errors.New("another instance is rotating the signing keys")
+	}
+	defer conn.ExecContext(context.Background(), "select release_lock(?)", signingKeysLockName)
+
+	return function()
+}
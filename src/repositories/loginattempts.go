@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// LoginAttempts is the audit trail of sign-ins, also used to throttle guessing.
+// Attempts rejected because of a lockout are recorded with the reason "locked"
+// and do not count as failures, so waiting out a lockout is always enough.
+type LoginAttempts interface {
+	Create(ctx context.Context, attempt models.LoginAttempt) error
+	SearchEmailFailures(ctx context.Context, email string, since time.Time) (models.LoginFailures, error)
+	SearchIPFailures(ctx context.Context, ip string, since time.Time) (models.LoginFailures, error)
+}
+
+type loginAttempts struct {
+	db *sql.DB
+}
+
+func NewRepositoryLoginAttempts(db *sql.DB) LoginAttempts {
+	return &loginAttempts{db}
+}
+
+func (l loginAttempts) Create(ctx context.Context, attempt models.LoginAttempt) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := l.db.PrepareContext(ctx, `
+		insert into login_attempts (user_id, email, ip, user_agent, success, reason) values (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	var userID interface{}
+	if attempt.UserID != 0 {
+		userID = attempt.UserID
+	}
+
+	if _, err = statement.ExecContext(ctx,
+		userID, attempt.Email, attempt.IP, attempt.UserAgent, attempt.Success, attempt.Reason,
+	); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+// SearchEmailFailures counts the failures since the last successful sign-in of the address.
+func (l loginAttempts) SearchEmailFailures(ctx context.Context, email string, since time.Time) (models.LoginFailures, error) {
+	return l.searchFailures(ctx, `
+		select count(*), max(createdat) from login_attempts
+		where email = ? and success = false and reason <> 'locked' and createdat > ?
+		and createdat > coalesce((
+			select max(createdat) from login_attempts where email = ? and success = true
+		), ?)
+	`, email, since, email, since)
+}
+
+// SearchIPFailures ignores successes, otherwise signing in to an account of its
+// own would let an attacker reset the counter of the address it guesses from.
+func (l loginAttempts) SearchIPFailures(ctx context.Context, ip string, since time.Time) (models.LoginFailures, error) {
+	return l.searchFailures(ctx, `
+		select count(*), max(createdat) from login_attempts
+		where ip = ? and success = false and reason <> 'locked' and createdat > ?
+	`, ip, since)
+}
+
+func (l loginAttempts) searchFailures(ctx context.Context, query string, arguments ...interface{}) (models.LoginFailures, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := l.db.QueryContext(ctx, query, arguments...)
+	if err != nil {
+		return models.LoginFailures{}, err
+	}
+	defer rows.Close()
+
+	var failures models.LoginFailures
+	if !rows.Next() {
+		return failures, rows.Err()
+	}
+
+	var lastAt sql.NullTime
+	if err = rows.Scan(&failures.Count, &lastAt); err != nil {
+		return models.LoginFailures{}, err
+	}
+	failures.LastAt = lastAt.Time
+
+	return failures, nil
+}
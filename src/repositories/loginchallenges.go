@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+)
+
+type LoginChallenges interface {
+	Create(ctx context.Context, challenge models.LoginChallenge) (uint64, error)
+	SearchToken(ctx context.Context, tokenHash string) (models.LoginChallenge, error)
+	Fail(ctx context.Context, ID uint64) error
+	Consume(ctx context.Context, ID uint64) (bool, error)
+}
+
+type loginChallenges struct {
+	db *sql.DB
+}
+
+func NewRepositoryLoginChallenges(db *sql.DB) LoginChallenges {
+	return &loginChallenges{db}
+}
+
+func (l loginChallenges) Create(ctx context.Context, challenge models.LoginChallenge) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := l.db.PrepareContext(ctx, "insert into login_challenges (user_id, token, expires_at) values (?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, challenge.UserID, challenge.TokenHash, challenge.ExpiresAt)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (l loginChallenges) SearchToken(ctx context.Context, tokenHash string) (models.LoginChallenge, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := l.db.QueryContext(ctx, `
+		select id, user_id, attempts, expires_at from login_challenges
+		where token = ? and used_at is null and expires_at > now()
+	`, tokenHash)
+	if err != nil {
+		return models.LoginChallenge{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.LoginChallenge{}, noRows(rows, "two_factor.invalid_challenge")
+	}
+
+	var challenge models.LoginChallenge
+	if err = rows.Scan(
+		&challenge.ID,
+		&challenge.UserID,
+		&challenge.Attempts,
+		&challenge.ExpiresAt,
+	); err != nil {
+		return models.LoginChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+func (l loginChallenges) Fail(ctx context.Context, ID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := l.db.PrepareContext(ctx, "update login_challenges set attempts = attempts + 1 where id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (l loginChallenges) Consume(ctx context.Context, ID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := l.db.PrepareContext(ctx, "update login_challenges set used_at = now() where id = ? and used_at is null")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, ID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
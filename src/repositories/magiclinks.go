@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type MagicLinks interface {
+	Create(ctx context.Context, link models.MagicLink) (uint64, error)
+	SearchEmailRequests(ctx context.Context, email string, since time.Time) (models.Requests, error)
+	Consume(ctx context.Context, tokenHash string) (models.MagicLink, error)
+}
+
+type magicLinks struct {
+	db *sql.DB
+}
+
+func NewRepositoryMagicLinks(db *sql.DB) MagicLinks {
+	return &magicLinks{db}
+}
+
+// Create also records requests for unknown emails, without user or token, so
+// they count towards the same rate limit as registered ones.
+func (m magicLinks) Create(ctx context.Context, link models.MagicLink) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := m.db.PrepareContext(ctx, "insert into magic_links (user_id, email, token, expires_at) values (?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	var userID, tokenHash interface{}
+	if link.UserID != 0 {
+		userID, tokenHash = link.UserID, link.TokenHash
+	}
+
+	result, err := statement.ExecContext(ctx, userID, link.Email, tokenHash, link.ExpiresAt)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (m magicLinks) SearchEmailRequests(ctx context.Context, email string, since time.Time) (models.Requests, error) {
+	return countRequests(ctx, m.db, "select count(*), min(createdat) from magic_links where email = ? and createdat > ?", email, since)
+}
+
+// Consume marks the link as used and returns it, so the same link can never
+// be used twice.
+func (m magicLinks) Consume(ctx context.Context, tokenHash string) (models.MagicLink, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.MagicLink{}, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		select id, user_id, email, expires_at, createdat from magic_links
+		where token = ? and used_at is null and expires_at > now()
+		for update
+	`, tokenHash)
+	if err != nil {
+		return models.MagicLink{}, err
+	}
+
+	if !rows.Next() {
+		err = noRows(rows, "magic_link.invalid_token")
+		rows.Close()
+		return models.MagicLink{}, err
+	}
+
+	var link models.MagicLink
+	err = rows.Scan(&link.ID, &link.UserID, &link.Email, &link.ExpiresAt, &link.CreatedAt)
+	rows.Close()
+	if err != nil {
+		return models.MagicLink{}, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "update magic_links set used_at = now() where id = ?", link.ID); err != nil {
+		return models.MagicLink{}, translate(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.MagicLink{}, err
+	}
+
+	return link, nil
+}
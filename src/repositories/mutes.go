@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+)
+
+type Mutes interface {
+	Mute(ctx context.Context, muterID, mutedID uint64) error
+	Unmute(ctx context.Context, muterID, mutedID uint64) error
+	SearchMuted(ctx context.Context, muterID uint64, params pagination.Params) ([]models.User, error)
+}
+
+type mutes struct {
+	db *sql.DB
+}
+
+func NewRepositoryMutes(db *sql.DB) Mutes {
+	return &mutes{db}
+}
+
+func (m mutes) Mute(ctx context.Context, muterID, mutedID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := m.db.PrepareContext(ctx, "insert ignore into mutes (muter_id, muted_id) values (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, muterID, mutedID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (m mutes) Unmute(ctx context.Context, muterID, mutedID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := m.db.PrepareContext(ctx, "delete from mutes where muter_id = ? and muted_id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, muterID, mutedID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (m mutes) SearchMuted(ctx context.Context, muterID uint64, params pagination.Params) ([]models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("u.createdat", "u.id")
+
+	rows, err := m.db.QueryContext(ctx, `
+		select u.id, u.name, u.nick, u.email, u.email_verified, u.createdat
+		from users u
+		inner join mutes m on u.id = m.muted_id
+		where m.muter_id = ? and `+condition+" "+params.OrderBy("u.createdat", "u.id"),
+		append([]interface{}{muterID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRelatedUsers(rows)
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type OAuthClients interface {
+	Create(ctx context.Context, client models.OAuthClient) (uint64, error)
+	SearchClientID(ctx context.Context, clientID string) (models.OAuthClient, error)
+	SearchOwner(ctx context.Context, ownerID uint64, params pagination.Params) ([]models.OAuthClient, error)
+	Delete(ctx context.Context, clientID string, ownerID uint64) (bool, error)
+}
+
+type oauthClients struct {
+	db *sql.DB
+}
+
+func NewRepositoryOAuthClients(db *sql.DB) OAuthClients {
+	return &oauthClients{db}
+}
+
+func (o oauthClients) Create(ctx context.Context, client models.OAuthClient) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, `
+		insert into oauth_clients (owner_id, client_id, secret, name, redirect_uris, confidential) values (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx,
+		client.OwnerID, client.ClientID, client.SecretHash, client.Name, strings.Join(client.RedirectURIs, "\n"), client.Confidential,
+	)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (o oauthClients) SearchClientID(ctx context.Context, clientID string) (models.OAuthClient, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx, `
+		select id, owner_id, client_id, secret, name, redirect_uris, confidential, createdat from oauth_clients
+		where client_id = ?
+	`, clientID)
+	if err != nil {
+		return models.OAuthClient{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.OAuthClient{}, noRows(rows, "oauth.client_not_found")
+	}
+
+	return scanOAuthClient(rows)
+}
+
+func (o oauthClients) SearchOwner(ctx context.Context, ownerID uint64, params pagination.Params) ([]models.OAuthClient, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("createdat", "id")
+
+	rows, err := o.db.QueryContext(ctx, `
+		select id, owner_id, client_id, secret, name, redirect_uris, confidential, createdat from oauth_clients
+		where owner_id = ? and `+condition+" "+params.OrderBy("createdat", "id"),
+		append([]interface{}{ownerID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []models.OAuthClient
+	for rows.Next() {
+		client, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		clients = append(clients, client)
+	}
+
+	return clients, nil
+}
+
+func (o oauthClients) Delete(ctx context.Context, clientID string, ownerID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, "delete from oauth_clients where client_id = ? and owner_id = ?")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, clientID, ownerID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func scanOAuthClient(rows *sql.Rows) (models.OAuthClient, error) {
+	var client models.OAuthClient
+	var redirectURIs string
+	if err := rows.Scan(
+		&client.ID,
+		&client.OwnerID,
+		&client.ClientID,
+		&client.SecretHash,
+		&client.Name,
+		&redirectURIs,
+		&client.Confidential,
+		&client.CreatedAt,
+	); err != nil {
+		return models.OAuthClient{}, err
+	}
+
+	client.RedirectURIs = strings.Split(redirectURIs, "\n")
+	return client, nil
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type OAuthCodes interface {
+	Create(ctx context.Context, code models.OAuthCode) (uint64, error)
+	SearchCode(ctx context.Context, codeHash string) (models.OAuthCode, error)
+	Use(ctx context.Context, ID uint64) (bool, error)
+}
+
+type oauthCodes struct {
+	db *sql.DB
+}
+
+func NewRepositoryOAuthCodes(db *sql.DB) OAuthCodes {
+	return &oauthCodes{db}
+}
+
+func (o oauthCodes) Create(ctx context.Context, code models.OAuthCode) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, `
+		insert into oauth_codes (client_id, user_id, code, redirect_uri, scopes, code_challenge, expires_at)
+		values (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx,
+		code.ClientID, code.UserID, code.CodeHash, code.RedirectURI, strings.Join(code.Scopes, " "), code.CodeChallenge, code.ExpiresAt,
+	)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+// SearchCode also finds used codes, so a replayed code can be detected and the
+// tokens issued for it revoked.
+func (o oauthCodes) SearchCode(ctx context.Context, codeHash string) (models.OAuthCode, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx, `
+		select id, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at, used_at from oauth_codes
+		where code = ?
+	`, codeHash)
+	if err != nil {
+		return models.OAuthCode{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.OAuthCode{}, noRows(rows, "oauth.invalid_grant")
+	}
+
+	var code models.OAuthCode
+	var scopes string
+	if err = rows.Scan(
+		&code.ID,
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		&scopes,
+		&code.CodeChallenge,
+		&code.ExpiresAt,
+		&code.UsedAt,
+	); err != nil {
+		return models.OAuthCode{}, err
+	}
+
+	code.Scopes = strings.Fields(scopes)
+	return code, nil
+}
+
+func (o oauthCodes) Use(ctx context.Context, ID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, "update oauth_codes set used_at = now() where id = ? and used_at is null")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, ID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
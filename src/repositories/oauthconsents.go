@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type OAuthConsents interface {
+	Save(ctx context.Context, consent models.OAuthConsent) error
+	Search(ctx context.Context, userID, clientID uint64) (models.OAuthConsent, error)
+	SearchUser(ctx context.Context, userID uint64, params pagination.Params) ([]models.OAuthConsent, error)
+	Delete(ctx context.Context, userID, clientID uint64) (bool, error)
+	DeleteUser(ctx context.Context, userID uint64) error
+}
+
+type oauthConsents struct {
+	db *sql.DB
+}
+
+func NewRepositoryOAuthConsents(db *sql.DB) OAuthConsents {
+	return &oauthConsents{db}
+}
+
+// Save records the scopes granted to the client, replacing an earlier consent.
+func (o oauthConsents) Save(ctx context.Context, consent models.OAuthConsent) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, `
+		insert into oauth_consents (user_id, client_id, scopes) values (?, ?, ?)
+		on duplicate key update scopes = values(scopes)
+	`)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, consent.UserID, consent.ClientID, strings.Join(consent.Scopes, " ")); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (o oauthConsents) Search(ctx context.Context, userID, clientID uint64) (models.OAuthConsent, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx, `
+		select c.id, c.user_id, c.client_id, o.client_id, o.name, c.scopes, c.createdat, c.updatedat
+		from oauth_consents c
+		inner join oauth_clients o on o.id = c.client_id
+		where c.user_id = ? and c.client_id = ?
+	`, userID, clientID)
+	if err != nil {
+		return models.OAuthConsent{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.OAuthConsent{}, noRows(rows, "oauth.consent_not_found")
+	}
+
+	return scanOAuthConsent(rows)
+}
+
+func (o oauthConsents) SearchUser(ctx context.Context, userID uint64, params pagination.Params) ([]models.OAuthConsent, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("c.createdat", "c.id")
+
+	rows, err := o.db.QueryContext(ctx, `
+		select c.id, c.user_id, c.client_id, o.client_id, o.name, c.scopes, c.createdat, c.updatedat
+		from oauth_consents c
+		inner join oauth_clients o on o.id = c.client_id
+		where c.user_id = ? and `+condition+" "+params.OrderBy("c.createdat", "c.id"),
+		append([]interface{}{userID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var consents []models.OAuthConsent
+	for rows.Next() {
+		consent, err := scanOAuthConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		consents = append(consents, consent)
+	}
+
+	return consents, nil
+}
+
+func (o oauthConsents) Delete(ctx context.Context, userID, clientID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, "delete from oauth_consents where user_id = ? and client_id = ?")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, userID, clientID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func scanOAuthConsent(rows *sql.Rows) (models.OAuthConsent, error) {
+	var consent models.OAuthConsent
+	var scopes string
+	if err := rows.Scan(
+		&consent.ID,
+		&consent.UserID,
+		&consent.ClientID,
+		&consent.Client,
+		&consent.ClientName,
+		&scopes,
+		&consent.CreatedAt,
+		&consent.UpdatedAt,
+	); err != nil {
+		return models.OAuthConsent{}, err
+	}
+
+	consent.Scopes = strings.Fields(scopes)
+	return consent, nil
+}
+
+func (o oauthConsents) DeleteUser(ctx context.Context, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, "delete from oauth_consents where user_id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, userID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type OAuthTokens interface {
+	Create(ctx context.Context, token models.OAuthToken) (uint64, error)
+	SearchAccessToken(ctx context.Context, accessTokenHash string) (models.OAuthToken, error)
+	SearchRefreshToken(ctx context.Context, refreshTokenHash string) (models.OAuthToken, error)
+	Revoke(ctx context.Context, ID uint64) (bool, error)
+	RevokeCode(ctx context.Context, codeID uint64) error
+	RevokeConsent(ctx context.Context, userID, clientID uint64) error
+	RevokeUser(ctx context.Context, userID uint64) error
+}
+
+type oauthTokens struct {
+	db *sql.DB
+}
+
+func NewRepositoryOAuthTokens(db *sql.DB) OAuthTokens {
+	return &oauthTokens{db}
+}
+
+func (o oauthTokens) Create(ctx context.Context, token models.OAuthToken) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, `
+		insert into oauth_tokens (client_id, user_id, code_id, access_token, refresh_token, scopes, expires_at, refresh_expires_at)
+		values (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	var codeID interface{}
+	if token.CodeID != 0 {
+		codeID = token.CodeID
+	}
+
+	result, err := statement.ExecContext(ctx,
+		token.ClientID, token.UserID, codeID, token.AccessTokenHash, token.RefreshTokenHash,
+		strings.Join(token.Scopes, " "), token.ExpiresAt, token.RefreshExpiresAt,
+	)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (o oauthTokens) SearchAccessToken(ctx context.Context, accessTokenHash string) (models.OAuthToken, error) {
+	return o.search(ctx, "t.access_token = ? and t.expires_at > now()", accessTokenHash)
+}
+
+func (o oauthTokens) SearchRefreshToken(ctx context.Context, refreshTokenHash string) (models.OAuthToken, error) {
+	return o.search(ctx, "t.refresh_token = ? and t.refresh_expires_at > now()", refreshTokenHash)
+}
+
+func (o oauthTokens) search(ctx context.Context, condition string, tokenHash string) (models.OAuthToken, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := o.db.QueryContext(ctx, `
+		select t.id, t.client_id, c.client_id, t.user_id, coalesce(t.code_id, 0), t.scopes, t.expires_at, t.refresh_expires_at, t.createdat
+		from oauth_tokens t
+		inner join oauth_clients c on c.id = t.client_id
+		where `+condition+` and t.revoked_at is null
+	`, tokenHash)
+	if err != nil {
+		return models.OAuthToken{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.OAuthToken{}, noRows(rows, "oauth.invalid_token")
+	}
+
+	var token models.OAuthToken
+	var scopes string
+	if err = rows.Scan(
+		&token.ID,
+		&token.ClientID,
+		&token.Client,
+		&token.UserID,
+		&token.CodeID,
+		&scopes,
+		&token.ExpiresAt,
+		&token.RefreshExpiresAt,
+		&token.CreatedAt,
+	); err != nil {
+		return models.OAuthToken{}, err
+	}
+
+	token.Scopes = strings.Fields(scopes)
+	return token, nil
+}
+
+// Revoke only reports true to the first caller, so a refresh token cannot be
+// rotated twice by concurrent requests.
+func (o oauthTokens) Revoke(ctx context.Context, ID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, "update oauth_tokens set revoked_at = now() where id = ? and revoked_at is null")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, ID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (o oauthTokens) RevokeCode(ctx context.Context, codeID uint64) error {
+	return o.revoke(ctx, "code_id = ?", codeID)
+}
+
+func (o oauthTokens) RevokeConsent(ctx context.Context, userID, clientID uint64) error {
+	return o.revoke(ctx, "user_id = ? and client_id = ?", userID, clientID)
+}
+
+func (o oauthTokens) RevokeUser(ctx context.Context, userID uint64) error {
+	return o.revoke(ctx, "user_id = ?", userID)
+}
+
+func (o oauthTokens) revoke(ctx context.Context, condition string, arguments ...interface{}) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, "update oauth_tokens set revoked_at = now() where "+condition+" and revoked_at is null")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, arguments...); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+)
+
+type OIDCLogins interface {
+	Create(ctx context.Context, login models.OIDCLogin) (uint64, error)
+	Consume(ctx context.Context, stateHash string) (models.OIDCLogin, error)
+}
+
+type oidcLogins struct {
+	db *sql.DB
+}
+
+func NewRepositoryOIDCLogins(db *sql.DB) OIDCLogins {
+	return &oidcLogins{db}
+}
+
+func (o oidcLogins) Create(ctx context.Context, login models.OIDCLogin) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := o.db.PrepareContext(ctx, `
+		insert into oidc_logins (state, nonce, code_verifier, expires_at) values (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, login.StateHash, login.Nonce, login.CodeVerifier, login.ExpiresAt)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+// Consume deletes the login it returns, so a state can only complete one
+// sign-in.
+func (o oidcLogins) Consume(ctx context.Context, stateHash string) (models.OIDCLogin, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.OIDCLogin{}, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		select id, nonce, code_verifier, expires_at from oidc_logins
+		where state = ? and expires_at > now()
+		for update
+	`, stateHash)
+	if err != nil {
+		return models.OIDCLogin{}, err
+	}
+
+	if !rows.Next() {
+		err = noRows(rows, "oidc.invalid_state")
+		rows.Close()
+		return models.OIDCLogin{}, err
+	}
+
+	login := models.OIDCLogin{StateHash: stateHash}
+	err = rows.Scan(&login.ID, &login.Nonce, &login.CodeVerifier, &login.ExpiresAt)
+	rows.Close()
+	if err != nil {
+		return models.OIDCLogin{}, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "delete from oidc_logins where id = ?", login.ID); err != nil {
+		return models.OIDCLogin{}, translate(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.OIDCLogin{}, err
+	}
+
+	return login, nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"api/src/models"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type PasswordResets interface {
+	Create(ctx context.Context, reset models.PasswordReset) (uint64, error)
+	SearchEmailRequests(ctx context.Context, email string, since time.Time) (models.Requests, error)
+	SearchIPRequests(ctx context.Context, ip string, since time.Time) (models.Requests, error)
+	Consume(ctx context.Context, tokenHash string) (uint64, error)
+	RevokeUser(ctx context.Context, userID uint64) error
+}
+
+type passwordResets struct {
+	db *sql.DB
+}
+
+func NewRepositoryPasswordResets(db *sql.DB) PasswordResets {
+	return &passwordResets{db}
+}
+
+// Create also records requests for unknown emails, without user or token, so
+// they count towards the same rate limits as registered ones.
+func (p passwordResets) Create(ctx context.Context, reset models.PasswordReset) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "insert into password_resets (user_id, token, email, ip, expires_at) values (?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	var userID, tokenHash interface{}
+	if reset.UserID != 0 {
+		userID, tokenHash = reset.UserID, reset.TokenHash
+	}
+
+	result, err := statement.ExecContext(ctx, userID, tokenHash, reset.Email, reset.IP, reset.ExpiresAt)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (p passwordResets) SearchEmailRequests(ctx context.Context, email string, since time.Time) (models.Requests, error) {
+	return countRequests(ctx, p.db, "select count(*), min(createdat) from password_resets where email = ? and createdat > ?", email, since)
+}
+
+func (p passwordResets) SearchIPRequests(ctx context.Context, ip string, since time.Time) (models.Requests, error) {
+	return countRequests(ctx, p.db, "select count(*), min(createdat) from password_resets where ip = ? and createdat > ?", ip, since)
+}
+
+// Consume marks the token as used and returns the user it belongs to, so the
+// same link can never reset the password twice.
+func (p passwordResets) Consume(ctx context.Context, tokenHash string) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		select id, user_id from password_resets
+		where token = ? and used_at is null and expires_at > now()
+		for update
+	`, tokenHash)
+	if err != nil {
+		return 0, err
+	}
+
+	if !rows.Next() {
+		err = noRows(rows, "password_reset.invalid_token")
+		rows.Close()
+		return 0, err
+	}
+
+	var ID, userID uint64
+	err = rows.Scan(&ID, &userID)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "update password_resets set used_at = now() where id = ?", ID); err != nil {
+		return 0, translate(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (p passwordResets) RevokeUser(ctx context.Context, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "update password_resets set used_at = now() where user_id = ? and used_at is null")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, userID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
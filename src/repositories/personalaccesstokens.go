@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+	"strings"
+)
+
+type PersonalAccessTokens interface {
+	Create(ctx context.Context, token models.PersonalAccessToken) (uint64, error)
+	SearchUser(ctx context.Context, userID uint64, params pagination.Params) ([]models.PersonalAccessToken, error)
+	SearchToken(ctx context.Context, tokenHash string) (models.PersonalAccessToken, error)
+	Revoke(ctx context.Context, ID, userID uint64) (bool, error)
+	RevokeUser(ctx context.Context, userID uint64) error
+	Touch(ctx context.Context, ID uint64) error
+}
+
+type personalAccessTokens struct {
+	db *sql.DB
+}
+
+func NewRepositoryPersonalAccessTokens(db *sql.DB) PersonalAccessTokens {
+	return &personalAccessTokens{db}
+}
+
+func (p personalAccessTokens) Create(ctx context.Context, token models.PersonalAccessToken) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, `
+		insert into personal_access_tokens (user_id, name, token, scopes, expires_at) values (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, token.UserID, token.Name, token.TokenHash, strings.Join(token.Scopes, " "), token.ExpiresAt)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (p personalAccessTokens) SearchUser(ctx context.Context, userID uint64, params pagination.Params) ([]models.PersonalAccessToken, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("createdat", "id")
+
+	rows, err := p.db.QueryContext(ctx, `
+		select id, user_id, name, scopes, expires_at, last_used_at, createdat from personal_access_tokens
+		where user_id = ? and revoked_at is null and (expires_at is null or expires_at > now()) and `+
+		condition+" "+params.OrderBy("createdat", "id"),
+		append([]interface{}{userID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []models.PersonalAccessToken
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func (p personalAccessTokens) SearchToken(ctx context.Context, tokenHash string) (models.PersonalAccessToken, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, `
+		select id, user_id, name, scopes, expires_at, last_used_at, createdat from personal_access_tokens
+		where token = ? and revoked_at is null and (expires_at is null or expires_at > now())
+	`, tokenHash)
+	if err != nil {
+		return models.PersonalAccessToken{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.PersonalAccessToken{}, noRows(rows, "token.not_found")
+	}
+
+	return scanPersonalAccessToken(rows)
+}
+
+func (p personalAccessTokens) Revoke(ctx context.Context, ID, userID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, `
+		update personal_access_tokens set revoked_at = now() where id = ? and user_id = ? and revoked_at is null
+	`)
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, ID, userID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (p personalAccessTokens) RevokeUser(ctx context.Context, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "update personal_access_tokens set revoked_at = now() where user_id = ? and revoked_at is null")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, userID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (p personalAccessTokens) Touch(ctx context.Context, ID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, `
+		update personal_access_tokens set last_used_at = now()
+		where id = ? and (last_used_at is null or last_used_at < now() - interval 1 minute)
+	`)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func scanPersonalAccessToken(rows *sql.Rows) (models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	var scopes string
+	if err := rows.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&scopes,
+		&token.ExpiresAt,
+		&token.LastUsedAt,
+		&token.CreatedAt,
+	); err != nil {
+		return models.PersonalAccessToken{}, err
+	}
+
+	token.Scopes = strings.Fields(scopes)
+	return token, nil
+}
@@ -2,27 +2,62 @@ package repositories
 
 import (
 	"api/src/models"
+	"api/src/pagination"
+	"context"
 	"database/sql"
 )
 
-type Publications struct {
+const selectPublications = `
+	select p.id, p.title, p.content, p.author_id, u.nick, p.createdat,
+	(select count(*) from likes l where l.publication_id = p.id) as likes,
+	exists(select 1 from likes l where l.publication_id = p.id and l.user_id = ?) as liked_by_me,
+	(select count(*) from comments c where c.publication_id = p.id) as comments
+	from publications p
+	inner join users u on u.id = p.author_id
+`
+
+// visiblePublication shows the author all of their publications. Everyone else
+// only sees those not hidden by moderators, from public accounts or accounts
+// they follow, and whose author did not block them nor was blocked by them.
+// It takes the viewer four times.
+var visiblePublication = `(p.author_id = ? or (p.hidden_at is null and (u.private = false or exists(
+	select 1 from followers v where v.user_id = p.author_id and v.follower_id = ?
+)) and ` + notBlocked("p.author_id") + `))`
+
+type Publications interface {
+	Create(ctx context.Context, publication models.Publication) (uint64, error)
+	SearchID(ctx context.Context, publicationID, viewerID uint64) (models.Publication, error)
+	SearchAuthor(ctx context.Context, publicationID uint64) (uint64, error)
+	Search(ctx context.Context, userID uint64, params pagination.Params) ([]models.Publication, error)
+	Update(ctx context.Context, publicationID uint64, publication models.Publication) error
+	Delete(ctx context.Context, publicationID uint64) error
+	SearchUser(ctx context.Context, userID, viewerID uint64, params pagination.Params) ([]models.Publication, error)
+	Like(ctx context.Context, publicationID, userID uint64) error
+	Unlike(ctx context.Context, publicationID, userID uint64) error
+	SearchLikes(ctx context.Context, publicationID, viewerID uint64, params pagination.Params) ([]models.Like, error)
+}
+
+type publications struct {
 	db *sql.DB
 }
 
-func NewRepositoryPublications(db *sql.DB) *Publications {
-	return &Publications{db}
+func NewRepositoryPublications(db *sql.DB) Publications {
+	return &publications{db}
 }
 
-func (p Publications) Create(publication models.Publication) (uint64, error) {
-	statement, err := p.db.Prepare("insert into publications (title, content, author_id) values (?, ?, ?)")
+func (p publications) Create(ctx context.Context, publication models.Publication) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "insert into publications (title, content, author_id) values (?, ?, ?)")
 	if err != nil {
 		return 0, err
 	}
 	defer statement.Close()
 
-	result, err := statement.Exec(publication.Title, publication.Content, publication.AuthorID)
+	result, err := statement.ExecContext(ctx, publication.Title, publication.Content, publication.AuthorID)
 	if err != nil {
-		return 0, err
+		return 0, translate(err)
 	}
 
 	lastIDInserted, err := result.LastInsertId()
@@ -33,148 +68,213 @@ func (p Publications) Create(publication models.Publication) (uint64, error) {
 	return uint64(lastIDInserted), nil
 }
 
-func (p Publications) SearchID(publicationID uint64) (models.Publication, error) {
-	rows, err := p.db.Query(`
-		select p.*, u.nick from
-		publications p inner join users u
-		on u.id = p.author_id where p.id = ?
-	`, publicationID)
+func (p publications) SearchID(ctx context.Context, publicationID, viewerID uint64) (models.Publication, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, selectPublications+"where p.id = ? and "+visiblePublication,
+		viewerID, publicationID, viewerID, viewerID, viewerID, viewerID,
+	)
 	if err != nil {
 		return models.Publication{}, err
 	}
 	defer rows.Close()
 
-	var publication models.Publication
-
-	if rows.Next() {
-		if err = rows.Scan(
-			&publication.ID, &publication.Title, &publication.Content, &publication.AuthorID, &publication.Likes,
-			&publication.CreatedAt, &publication.AuthorNick,
-		); err != nil {
-			return models.Publication{}, err
-		}
+	if !rows.Next() {
+		return models.Publication{}, noRows(rows, "publication.not_found")
 	}
 
-	return publication, nil
+	return scanPublication(rows)
 }
 
-func (p Publications) Search(userID uint64) ([]models.Publication, error) {
-	rows, err := p.db.Query(`
-		select distinct p.*, u.nick from
-		publications p 
-		inner join users u on u.id = p.author_id 
-		inner join followers s on p.author_id = s.user_id
-		where p.id = ? or s.follower_id = ?
-	`, userID, userID)
+// SearchAuthor ignores visibility rules, for moderation.
+func (p publications) SearchAuthor(ctx context.Context, publicationID uint64) (uint64, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := p.db.QueryContext(ctx, "select author_id from publications where id = ?", publicationID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 	defer rows.Close()
 
-	var publications []models.Publication
+	if !rows.Next() {
+		return 0, noRows(rows, "publication.not_found")
+	}
 
-	if rows.Next() {
-		var publication models.Publication
-		if err = rows.Scan(
-			&publication.ID, &publication.Title, &publication.Content, &publication.AuthorID, &publication.Likes,
-			&publication.CreatedAt, &publication.AuthorNick,
-		); err != nil {
-			return nil, err
-		}
-		publications = append(publications, publication)
+	var authorID uint64
+	if err = rows.Scan(&authorID); err != nil {
+		return 0, err
 	}
 
-	return publications, nil
+	return authorID, nil
 }
 
-func (p Publications) Update(publicationID uint64, publication models.Publication) error {
-	statement, err := p.db.Prepare("update publications set title = ?, content = ? where id = ?")
+func (p publications) Search(ctx context.Context, userID uint64, params pagination.Params) ([]models.Publication, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("p.createdat", "p.id")
+
+	rows, err := p.db.QueryContext(ctx, selectPublications+`
+		where (p.author_id = ? or exists(
+			select 1 from followers s where s.user_id = p.author_id and s.follower_id = ?
+		)) and not exists(
+			select 1 from mutes m where m.muter_id = ? and m.muted_id = p.author_id
+		) and `+visiblePublication+" and "+condition+" "+params.OrderBy("p.createdat", "p.id"),
+		append([]interface{}{userID, userID, userID, userID, userID, userID, userID, userID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPublications(rows)
+}
+
+func (p publications) Update(ctx context.Context, publicationID uint64, publication models.Publication) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "update publications set title = ?, content = ? where id = ?")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(publication.Title, publication.Content, publicationID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, publication.Title, publication.Content, publicationID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (p Publications) Delete(publicationID uint64) error {
-	statement, err := p.db.Prepare("delete from publications where id = ?")
+func (p publications) Delete(ctx context.Context, publicationID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "delete from publications where id = ?")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(publicationID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, publicationID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (p Publications) SearchUser(userID uint64) ([]models.Publication, error) {
-	rows, err := p.db.Query(`
-		select p.*, u.nick from publications p 
-		inner join users u on u.id = p.author_id 
-		where p.author_id = ?
-	`, userID)
+func (p publications) SearchUser(ctx context.Context, userID, viewerID uint64, params pagination.Params) ([]models.Publication, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("p.createdat", "p.id")
+
+	rows, err := p.db.QueryContext(ctx, selectPublications+`
+		where p.author_id = ? and `+visiblePublication+" and "+condition+" "+params.OrderBy("p.createdat", "p.id"),
+		append([]interface{}{viewerID, userID, viewerID, viewerID, viewerID, viewerID}, arguments...)...,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var publications []models.Publication
-
-	if rows.Next() {
-		var publication models.Publication
-		if err = rows.Scan(
-			&publication.ID, &publication.Title, &publication.Content, &publication.AuthorID, &publication.Likes,
-			&publication.CreatedAt, &publication.AuthorNick,
-		); err != nil {
-			return nil, err
-		}
-		publications = append(publications, publication)
-	}
-
-	return publications, nil
+	return scanPublications(rows)
 }
 
-func (p Publications) LikePublication(publicationID uint64) error {
-	statement, err := p.db.Prepare("update publications set likes = likes + 1 where id = ?")
+func (p publications) Like(ctx context.Context, publicationID, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "insert ignore into likes (user_id, publication_id) values (?, ?)")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(publicationID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, userID, publicationID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (p Publications) DislikePublication(publicationID uint64) error {
-	statement, err := p.db.Prepare(`
-		update publications set likes = 
-		case 
-			when likes > 0 
-			then likes - 1 
-		else 
-			0 
-		end
-		where id = ?
-	`)
+func (p publications) Unlike(ctx context.Context, publicationID, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := p.db.PrepareContext(ctx, "delete from likes where user_id = ? and publication_id = ?")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(publicationID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, userID, publicationID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
+
+func (p publications) SearchLikes(ctx context.Context, publicationID, viewerID uint64, params pagination.Params) ([]models.Like, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("l.createdat", "u.id")
+
+	rows, err := p.db.QueryContext(ctx, `
+		select l.publication_id, u.id, u.name, u.nick, l.createdat
+		from users u
+		inner join likes l on u.id = l.user_id
+		where l.publication_id = ? and `+notBlocked("u.id")+" and "+condition+" "+params.OrderBy("l.createdat", "u.id"),
+		append([]interface{}{publicationID, viewerID, viewerID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var likes []models.Like
+	for rows.Next() {
+		var like models.Like
+		if err = rows.Scan(
+			&like.PublicationID,
+			&like.UserID,
+			&like.UserName,
+			&like.UserNick,
+			&like.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		likes = append(likes, like)
+	}
+
+	return likes, nil
+}
+
+func scanPublications(rows *sql.Rows) ([]models.Publication, error) {
+	var publications []models.Publication
+	for rows.Next() {
+		publication, err := scanPublication(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		publications = append(publications, publication)
+	}
+
+	return publications, nil
+}
+
+func scanPublication(rows *sql.Rows) (models.Publication, error) {
+	var publication models.Publication
+	err := rows.Scan(
+		&publication.ID, &publication.Title, &publication.Content, &publication.AuthorID, &publication.AuthorNick,
+		&publication.CreatedAt, &publication.Likes, &publication.LikedByMe, &publication.Comments,
+	)
+
+	return publication, err
+}
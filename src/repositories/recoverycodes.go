@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+type RecoveryCodes interface {
+	Replace(ctx context.Context, userID uint64, codeHashes []string) error
+	Use(ctx context.Context, userID uint64, codeHash string) (bool, error)
+}
+
+type recoveryCodes struct {
+	db *sql.DB
+}
+
+func NewRepositoryRecoveryCodes(db *sql.DB) RecoveryCodes {
+	return &recoveryCodes{db}
+}
+
+// Replace discards every previous code of the user, used or not.
+func (c recoveryCodes) Replace(ctx context.Context, userID uint64, codeHashes []string) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "delete from recovery_codes where user_id = ?", userID); err != nil {
+		return translate(err)
+	}
+
+	for _, codeHash := range codeHashes {
+		if _, err = tx.ExecContext(ctx, "insert into recovery_codes (user_id, code) values (?, ?)", userID, codeHash); err != nil {
+			return translate(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (c recoveryCodes) Use(ctx context.Context, userID uint64, codeHash string) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := c.db.PrepareContext(ctx, "update recovery_codes set used_at = now() where user_id = ? and code = ? and used_at is null")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, userID, codeHash)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"api/src/domain"
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+)
+
+const selectReports = `
+	select id, kind, coalesce(reporter_id, 0), coalesce(user_id, 0), coalesce(publication_id, 0), reason, details, status,
+	user_nick, publication_title, coalesce(publication_content, ''), decided_at, createdat
+	from reports
+`
+
+type Reports interface {
+	Create(ctx context.Context, report models.Report) (uint64, error)
+	Search(ctx context.Context, status string, params pagination.Params) ([]models.Report, error)
+	SearchID(ctx context.Context, ID uint64) (models.Report, error)
+	SearchActions(ctx context.Context, reportID uint64) ([]models.ReportAction, error)
+	Decide(ctx context.Context, report models.Report, action models.ReportAction) (bool, error)
+}
+
+type reports struct {
+	db *sql.DB
+}
+
+func NewRepositoryReports(db *sql.DB) Reports {
+	return &reports{db}
+}
+
+// Create refuses a report while the same reporter still has an open one on the
+// same target, so nobody can flood the moderation queue.
+func (r reports) Create(ctx context.Context, report models.Report) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	target, targetID := "user_id", report.UserID
+	if report.Kind == models.ReportPublication {
+		target, targetID = "publication_id", report.PublicationID
+	}
+
+	statement, err := r.db.PrepareContext(ctx, `
+		insert into reports (kind, reporter_id, user_id, publication_id, reason, details, user_nick, publication_title, publication_content)
+		select ?, ?, ?, ?, ?, ?, ?, ?, ? from dual
+		where not exists (
+			select 1 from reports where reporter_id = ? and kind = ? and `+target+` = ? and status = ?
+		)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	var publicationID, publicationContent interface{}
+	if report.PublicationID != 0 {
+		publicationID = report.PublicationID
+		publicationContent = report.PublicationContent
+	}
+
+	result, err := statement.ExecContext(ctx,
+		report.Kind, report.ReporterID, report.UserID, publicationID, report.Reason, report.Details,
+		report.UserNick, report.PublicationTitle, publicationContent,
+		report.ReporterID, report.Kind, targetID, models.ReportOpen,
+	)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rowsAffected != 1 {
+		return 0, domain.Conflict("report.duplicate")
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (r reports) Search(ctx context.Context, status string, params pagination.Params) ([]models.Report, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("createdat", "id")
+
+	rows, err := r.db.QueryContext(ctx, selectReports+"where status = ? and "+condition+" "+params.OrderBy("createdat", "id"),
+		append([]interface{}{status}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []models.Report
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (r reports) SearchID(ctx context.Context, ID uint64) (models.Report, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, selectReports+"where id = ?", ID)
+	if err != nil {
+		return models.Report{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.Report{}, noRows(rows, "report.not_found")
+	}
+
+	return scanReport(rows)
+}
+
+func (r reports) SearchActions(ctx context.Context, reportID uint64) ([]models.ReportAction, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, `
+		select a.id, a.report_id, coalesce(a.moderator_id, 0), coalesce(u.nick, ''), a.action, a.note, a.createdat
+		from report_actions a
+		left join users u on u.id = a.moderator_id
+		where a.report_id = ?
+		order by a.createdat, a.id
+	`, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actions []models.ReportAction
+	for rows.Next() {
+		var action models.ReportAction
+		if err = rows.Scan(
+			&action.ID,
+			&action.ReportID,
+			&action.ModeratorID,
+			&action.ModeratorNick,
+			&action.Action,
+			&action.Note,
+			&action.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// Decide claims the report, if still open, takes the action on the reported
+// content or its author and records who decided it, all in one transaction, so
+// concurrent moderators cannot act on the same report twice.
+func (r reports) Decide(ctx context.Context, report models.Report, action models.ReportAction) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"update reports set status = ?, decided_at = now() where id = ? and status = ?",
+		action.Status(), report.ID, models.ReportOpen,
+	)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected != 1 {
+		return false, nil
+	}
+
+	switch action.Action {
+	case models.ActionHide:
+		_, err = tx.ExecContext(ctx, "update publications set hidden_at = coalesce(hidden_at, now()) where id = ?", report.PublicationID)
+	case models.ActionDelete:
+		_, err = tx.ExecContext(ctx, "delete from publications where id = ?", report.PublicationID)
+	case models.ActionSuspend:
+		err = suspendUser(ctx, tx, report.UserID)
+	}
+	if err != nil {
+		return false, translate(err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		"insert into report_actions (report_id, moderator_id, action, note) values (?, ?, ?, ?)",
+		report.ID, action.ModeratorID, action.Action, action.Note,
+	); err != nil {
+		return false, translate(err)
+	}
+
+	return true, tx.Commit()
+}
+
+func scanReport(rows *sql.Rows) (models.Report, error) {
+	var report models.Report
+	err := rows.Scan(
+		&report.ID, &report.Kind, &report.ReporterID, &report.UserID, &report.PublicationID,
+		&report.Reason, &report.Details, &report.Status,
+		&report.UserNick, &report.PublicationTitle, &report.PublicationContent, &report.DecidedAt, &report.CreatedAt,
+	)
+
+	return report, err
+}
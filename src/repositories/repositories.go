@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"api/src/config"
+	"api/src/domain"
+	"api/src/models"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	mysqlDuplicateEntry     = 1062
+	mysqlForeignKeyMismatch = 1452
+)
+
+func withReadTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, config.DatabaseReadTimeout)
+}
+
+func withWriteTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, config.DatabaseWriteTimeout)
+}
+
+func translate(err error) error {
+	var mysqlError *mysql.MySQLError
+	if !errors.As(err, &mysqlError) {
+		return err
+	}
+
+	switch mysqlError.Number {
+	case mysqlDuplicateEntry:
+		// The message ends with "for key 'users.nick'" (or just 'nick' on older servers).
+		key := mysqlError.Message[strings.LastIndex(mysqlError.Message, " ")+1:]
+		key = strings.Trim(key, "'")
+		key = key[strings.LastIndex(key, ".")+1:]
+		return &domain.Error{Kind: domain.KindConflict, Key: "record.duplicate", Args: []interface{}{key}, Err: err}
+	case mysqlForeignKeyMismatch:
+		return &domain.Error{Kind: domain.KindNotFound, Key: "record.referenced_not_found", Err: err}
+	}
+
+	return err
+}
+
+func noRows(rows *sql.Rows, key string) error {
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return domain.NotFound(key)
+}
+
+// countRequests counts the rows a query matches since the given time, along
+// with when the first one was made. The query selects count(*), min(createdat).
+func countRequests(ctx context.Context, db *sql.DB, query string, args ...interface{}) (models.Requests, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.Requests{}, err
+	}
+	defer rows.Close()
+
+	var requests models.Requests
+	if !rows.Next() {
+		return requests, rows.Err()
+	}
+
+	var firstAt sql.NullTime
+	if err = rows.Scan(&requests.Count, &firstAt); err != nil {
+		return models.Requests{}, err
+	}
+	requests.FirstAt = firstAt.Time
+
+	return requests, nil
+}
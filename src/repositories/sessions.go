@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"api/src/models"
+	"api/src/pagination"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Sessions interface {
+	Create(ctx context.Context, session models.Session) (uint64, error)
+	SearchRefreshToken(ctx context.Context, refreshTokenHash string) (models.Session, error)
+	SearchRetiredRefreshToken(ctx context.Context, refreshTokenHash string) (uint64, error)
+	Rotate(ctx context.Context, ID uint64, previousHash, refreshTokenHash, tokenID string, expiresAt time.Time) (bool, error)
+	Revoke(ctx context.Context, ID uint64) error
+	RevokeUser(ctx context.Context, userID uint64) error
+	RevokeUserSession(ctx context.Context, ID, userID uint64) (bool, error)
+	Active(ctx context.Context, ID, userID uint64) (bool, error)
+	Touch(ctx context.Context, ID uint64) error
+	SearchUser(ctx context.Context, userID uint64, params pagination.Params) ([]models.Session, error)
+}
+
+type sessions struct {
+	db *sql.DB
+}
+
+func NewRepositorySessions(db *sql.DB) Sessions {
+	return &sessions{db}
+}
+
+func (s sessions) Create(ctx context.Context, session models.Session) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := s.db.PrepareContext(ctx, `
+		insert into sessions (user_id, refresh_token, token_id, user_agent, ip, expires_at) values (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx,
+		session.UserID, session.RefreshTokenHash, session.TokenID, session.UserAgent, session.IP, session.ExpiresAt,
+	)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	lastIDInserted, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(lastIDInserted), nil
+}
+
+func (s sessions) SearchRefreshToken(ctx context.Context, refreshTokenHash string) (models.Session, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		select id, user_id, expires_at, createdat from sessions
+		where refresh_token = ? and revoked_at is null and expires_at > now()
+	`, refreshTokenHash)
+	if err != nil {
+		return models.Session{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.Session{}, noRows(rows, "session.not_found")
+	}
+
+	var session models.Session
+	if err = rows.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.ExpiresAt,
+		&session.CreatedAt,
+	); err != nil {
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+// SearchRetiredRefreshToken answers the session a refresh token belonged to
+// before it was rotated, so a replayed token can take the session down.
+func (s sessions) SearchRetiredRefreshToken(ctx context.Context, refreshTokenHash string) (uint64, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "select session_id from retired_refresh_tokens where refresh_token = ?", refreshTokenHash)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, noRows(rows, "session.not_found")
+	}
+
+	var sessionID uint64
+	if err = rows.Scan(&sessionID); err != nil {
+		return 0, err
+	}
+
+	return sessionID, nil
+}
+
+// Rotate only replaces the refresh token that was presented, so of two
+// refreshes racing with the same token just one succeeds. The replaced token
+// is kept as retired to recognise it if it is ever presented again.
+func (s sessions) Rotate(ctx context.Context, ID uint64, previousHash, refreshTokenHash, tokenID string, expiresAt time.Time) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		update sessions set refresh_token = ?, token_id = ?, expires_at = ?, last_seen_at = now()
+		where id = ? and refresh_token = ? and revoked_at is null
+	`, refreshTokenHash, tokenID, expiresAt, ID, previousHash)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected != 1 {
+		return false, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, "insert into retired_refresh_tokens (refresh_token, session_id) values (?, ?)", previousHash, ID); err != nil {
+		return false, translate(err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (s sessions) Revoke(ctx context.Context, ID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := s.db.PrepareContext(ctx, "update sessions set revoked_at = now() where id = ? and revoked_at is null")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (s sessions) RevokeUser(ctx context.Context, userID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := s.db.PrepareContext(ctx, "update sessions set revoked_at = now() where user_id = ? and revoked_at is null")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, userID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (s sessions) Active(ctx context.Context, ID, userID uint64) (bool, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `
+		select 1 from sessions
+		where id = ? and user_id = ? and revoked_at is null and expires_at > now()
+	`, ID, userID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	return rows.Next(), rows.Err()
+}
+
+func (s sessions) RevokeUserSession(ctx context.Context, ID, userID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := s.db.PrepareContext(ctx, "update sessions set revoked_at = now() where id = ? and user_id = ? and revoked_at is null")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, ID, userID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// Touch refreshes last_seen_at at most once a minute, so authenticated
+// requests do not all turn into writes.
+func (s sessions) Touch(ctx context.Context, ID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := s.db.PrepareContext(ctx, `
+		update sessions set last_seen_at = now()
+		where id = ? and last_seen_at < now() - interval 1 minute
+	`)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (s sessions) SearchUser(ctx context.Context, userID uint64, params pagination.Params) ([]models.Session, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("createdat", "id")
+
+	rows, err := s.db.QueryContext(ctx, `
+		select id, user_id, user_agent, ip, expires_at, last_seen_at, createdat from sessions
+		where user_id = ? and revoked_at is null and expires_at > now() and `+condition+" "+params.OrderBy("createdat", "id"),
+		append([]interface{}{userID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var session models.Session
+		if err = rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.UserAgent,
+			&session.IP,
+			&session.ExpiresAt,
+			&session.LastSeenAt,
+			&session.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
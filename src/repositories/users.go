@@ -2,28 +2,61 @@ package repositories
 
 import (
 	"api/src/models"
+	"api/src/pagination"
+	"context"
 	"database/sql"
 	"fmt"
 )
 
-type Users struct {
+type Users interface {
+	Create(ctx context.Context, user models.User) (uint64, error)
+	Search(ctx context.Context, nameOuNick string, viewerID uint64, params pagination.Params) ([]models.User, error)
+	SearchID(ctx context.Context, ID uint64) (models.User, error)
+	SearchEmail(ctx context.Context, email string) (models.User, error)
+	Update(ctx context.Context, ID uint64, user models.User) error
+	Delete(ctx context.Context, ID uint64) error
+	Follow(ctx context.Context, userID, followerID uint64) error
+	Unfollowollow(ctx context.Context, userID, followerID uint64) error
+	SearchFollowers(ctx context.Context, userID, viewerID uint64, params pagination.Params) ([]models.User, error)
+	SearchFollowing(ctx context.Context, userID, viewerID uint64, params pagination.Params) ([]models.User, error)
+	UpdatePassword(ctx context.Context, ID uint64, password string) error
+	SearchPassword(ctx context.Context, id uint64) (string, error)
+	VerifyEmail(ctx context.Context, ID uint64, email string) error
+	SearchTwoFactor(ctx context.Context, ID uint64) (models.TwoFactor, error)
+	UpdateTwoFactor(ctx context.Context, ID uint64, secret string, enabled bool) error
+	UseTOTPStep(ctx context.Context, ID uint64, step int64) (bool, error)
+	SearchAccounts(ctx context.Context, params pagination.Params) ([]models.User, error)
+	UpdateRole(ctx context.Context, ID uint64, role string) error
+	Suspend(ctx context.Context, ID uint64, suspended bool) error
+	UpdatePrivate(ctx context.Context, ID uint64, private bool) error
+	IsFollowing(ctx context.Context, userID, followerID uint64) (bool, error)
+	RequestFollow(ctx context.Context, userID, followerID uint64) error
+	SearchFollowRequests(ctx context.Context, userID uint64, params pagination.Params) ([]models.FollowRequest, error)
+	ApproveFollowRequest(ctx context.Context, userID, followerID uint64) (bool, error)
+	DeleteFollowRequest(ctx context.Context, userID, followerID uint64) (bool, error)
+}
+
+type users struct {
 	db *sql.DB
 }
 
-func NewRepositoryUsers(db *sql.DB) *Users {
-	return &Users{db}
+func NewRepositoryUsers(db *sql.DB) Users {
+	return &users{db}
 }
 
-func (u Users) Create(user models.User) (uint64, error) {
-	statement, err := u.db.Prepare("insert into users (name, nick, email, password) values (?, ?, ?, ?)")
+func (u users) Create(ctx context.Context, user models.User) (uint64, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "insert into users (name, nick, email, password) values (?, ?, ?, ?)")
 	if err != nil {
 		return 0, err
 	}
 	defer statement.Close()
 
-	result, err := statement.Exec(user.Name, user.Nick, user.Email, user.Password)
+	result, err := statement.ExecContext(ctx, user.Name, user.Nick, user.Email, user.Password)
 	if err != nil {
-		return 0, err
+		return 0, translate(err)
 	}
 
 	lastIDInserted, err := result.LastInsertId()
@@ -34,12 +67,17 @@ func (u Users) Create(user models.User) (uint64, error) {
 	return uint64(lastIDInserted), nil
 }
 
-func (u Users) Search(nameOuNick string) ([]models.User, error) {
+func (u users) Search(ctx context.Context, nameOuNick string, viewerID uint64, params pagination.Params) ([]models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
 	nameOuNick = fmt.Sprintf("%%%s%%", nameOuNick)
+	condition, arguments := params.Where("createdat", "id")
 
-	rows, err := u.db.Query(
-		"select id, name, nick, email, createdat from users where name like ? or nick like ?",
-		nameOuNick, nameOuNick,
+	rows, err := u.db.QueryContext(ctx,
+		"select id, name, nick, email, email_verified, private, createdat from users where (name like ? or nick like ?) and "+
+			notBlocked("id")+" and "+condition+" "+params.OrderBy("createdat", "id"),
+		append([]interface{}{nameOuNick, nameOuNick, viewerID, viewerID}, arguments...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -54,6 +92,8 @@ func (u Users) Search(nameOuNick string) ([]models.User, error) {
 			&user.Name,
 			&user.Nick,
 			&user.Email,
+			&user.EmailVerified,
+			&user.Private,
 			&user.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -65,9 +105,12 @@ func (u Users) Search(nameOuNick string) ([]models.User, error) {
 	return users, nil
 }
 
-func (u Users) SearchID(ID uint64) (models.User, error) {
-	rows, err := u.db.Query(
-		"select id, name, nick, email, createdat from users where id = ?",
+func (u users) SearchID(ctx context.Context, ID uint64) (models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := u.db.QueryContext(ctx,
+		"select id, name, nick, email, email_verified, private, role, suspended_at, createdat from users where id = ?",
 		ID,
 	)
 	if err != nil {
@@ -75,26 +118,34 @@ func (u Users) SearchID(ID uint64) (models.User, error) {
 	}
 	defer rows.Close()
 
-	var user models.User
+	if !rows.Next() {
+		return models.User{}, noRows(rows, "user.not_found")
+	}
 
-	if rows.Next() {
-		if err = rows.Scan(
-			&user.ID,
-			&user.Name,
-			&user.Nick,
-			&user.Email,
-			&user.CreatedAt,
-		); err != nil {
-			return models.User{}, err
-		}
+	var user models.User
+	if err = rows.Scan(
+		&user.ID,
+		&user.Name,
+		&user.Nick,
+		&user.Email,
+		&user.EmailVerified,
+		&user.Private,
+		&user.Role,
+		&user.SuspendedAt,
+		&user.CreatedAt,
+	); err != nil {
+		return models.User{}, err
 	}
 
 	return user, nil
 }
 
-func (u Users) SearchEmail(email string) (models.User, error) {
-	rows, err := u.db.Query(
-		"select id, password from users where email = ?",
+func (u users) SearchEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := u.db.QueryContext(ctx,
+		"select id, email, password, role, suspended_at from users where email = ?",
 		email,
 	)
 	if err != nil {
@@ -102,83 +153,110 @@ func (u Users) SearchEmail(email string) (models.User, error) {
 	}
 	defer rows.Close()
 
+	if !rows.Next() {
+		return models.User{}, noRows(rows, "user.not_found")
+	}
+
 	var user models.User
-	if rows.Next() {
-		if err = rows.Scan(
-			&user.ID,
-			&user.Password,
-		); err != nil {
-			return models.User{}, err
-		}
+	if err = rows.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.SuspendedAt,
+	); err != nil {
+		return models.User{}, err
 	}
 
 	return user, nil
 }
 
-func (u Users) Update(ID uint64, user models.User) error {
-	statement, err := u.db.Prepare("update users set name = ?, nick = ?, email = ? where id = ?")
+func (u users) Update(ctx context.Context, ID uint64, user models.User) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	// MySQL assigns from left to right, so email_verified is compared against the
+	// old email and only survives when the address did not change.
+	statement, err := u.db.PrepareContext(ctx, `
+		update users set name = ?, nick = ?, email_verified = email_verified and email = ?, email = ?
+		where id = ?
+	`)
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(user.Name, user.Nick, user.Email, ID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, user.Name, user.Nick, user.Email, user.Email, ID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (u Users) Delete(ID uint64) error {
-	statement, err := u.db.Prepare("delete from users where id = ?")
+func (u users) Delete(ctx context.Context, ID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "delete from users where id = ?")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(ID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, ID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (u Users) Follow(userID, followerID uint64) error {
-	statement, err := u.db.Prepare("insert ignore into followers (user_id, follower_id) values (?, ?)")
+func (u users) Follow(ctx context.Context, userID, followerID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "insert ignore into followers (user_id, follower_id) values (?, ?)")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(userID, followerID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, userID, followerID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (u Users) Unfollowollow(userID, followerID uint64) error {
-	statement, err := u.db.Prepare("delete from followers where user_id = ? and follower_id = ?")
+func (u users) Unfollowollow(ctx context.Context, userID, followerID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "delete from followers where user_id = ? and follower_id = ?")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(userID, followerID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, userID, followerID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (u Users) SearchFollowers(userID uint64) ([]models.User, error) {
-	rows, err := u.db.Query(`
-		select u.id, u.name, u.nick, u.email, u.createdat 
+func (u users) SearchFollowers(ctx context.Context, userID, viewerID uint64, params pagination.Params) ([]models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("u.createdat", "u.id")
+
+	rows, err := u.db.QueryContext(ctx, `
+		select u.id, u.name, u.nick, u.email, u.email_verified, u.createdat 
 		from users u 
 		inner join followers s 
 		on u.id = s.follower_id
-		where s.follower_id = ?`,
-		userID,
+		where s.user_id = ? and `+notBlocked("u.id")+" and "+condition+" "+params.OrderBy("u.createdat", "u.id"),
+		append([]interface{}{userID, viewerID, viewerID}, arguments...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -193,6 +271,7 @@ func (u Users) SearchFollowers(userID uint64) ([]models.User, error) {
 			&follower.Name,
 			&follower.Nick,
 			&follower.Email,
+			&follower.EmailVerified,
 			&follower.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -203,14 +282,19 @@ func (u Users) SearchFollowers(userID uint64) ([]models.User, error) {
 	return followers, nil
 }
 
-func (u Users) SearchFollowing(userID uint64) ([]models.User, error) {
-	rows, err := u.db.Query(`
-		select u.id, u.name, u.nick, u.email, u.createdat 
+func (u users) SearchFollowing(ctx context.Context, userID, viewerID uint64, params pagination.Params) ([]models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("u.createdat", "u.id")
+
+	rows, err := u.db.QueryContext(ctx, `
+		select u.id, u.name, u.nick, u.email, u.email_verified, u.createdat 
 		from users u 
 		inner join followers s 
 		on u.id = s.user_id
-		where s.follower_id = ?`,
-		userID,
+		where s.follower_id = ? and `+notBlocked("u.id")+" and "+condition+" "+params.OrderBy("u.createdat", "u.id"),
+		append([]interface{}{userID, viewerID, viewerID}, arguments...)...,
 	)
 	if err != nil {
 		return nil, err
@@ -225,6 +309,7 @@ func (u Users) SearchFollowing(userID uint64) ([]models.User, error) {
 			&follower.Name,
 			&follower.Nick,
 			&follower.Email,
+			&follower.EmailVerified,
 			&follower.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -235,22 +320,28 @@ func (u Users) SearchFollowing(userID uint64) ([]models.User, error) {
 	return following, nil
 }
 
-func (u Users) UpdatePassword(ID uint64, password string) error {
-	statement, err := u.db.Prepare("update users set password = ? where id = ?")
+func (u users) UpdatePassword(ctx context.Context, ID uint64, password string) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "update users set password = ? where id = ?")
 	if err != nil {
 		return err
 	}
 	defer statement.Close()
 
-	if _, err = statement.Exec(password, ID); err != nil {
-		return err
+	if _, err = statement.ExecContext(ctx, password, ID); err != nil {
+		return translate(err)
 	}
 
 	return nil
 }
 
-func (u Users) SearchPassword(id uint64) (string, error) {
-	rows, err := u.db.Query(
+func (u users) SearchPassword(ctx context.Context, id uint64) (string, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := u.db.QueryContext(ctx,
 		"select password from users where id = ?",
 		id,
 	)
@@ -259,14 +350,363 @@ func (u Users) SearchPassword(id uint64) (string, error) {
 	}
 	defer rows.Close()
 
+	if !rows.Next() {
+		return "", noRows(rows, "user.not_found")
+	}
+
 	var user models.User
-	if rows.Next() {
+	if err = rows.Scan(
+		&user.Password,
+	); err != nil {
+		return "", err
+	}
+
+	return user.Password, nil
+}
+
+func (u users) VerifyEmail(ctx context.Context, ID uint64, email string) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "update users set email_verified = true where id = ? and email = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, ID, email); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (u users) SearchTwoFactor(ctx context.Context, ID uint64) (models.TwoFactor, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := u.db.QueryContext(ctx,
+		"select totp_secret, totp_enabled, totp_last_step from users where id = ?",
+		ID,
+	)
+	if err != nil {
+		return models.TwoFactor{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return models.TwoFactor{}, noRows(rows, "user.not_found")
+	}
+
+	var twoFactor models.TwoFactor
+	if err = rows.Scan(
+		&twoFactor.Secret,
+		&twoFactor.Enabled,
+		&twoFactor.LastStep,
+	); err != nil {
+		return models.TwoFactor{}, err
+	}
+
+	return twoFactor, nil
+}
+
+func (u users) UpdateTwoFactor(ctx context.Context, ID uint64, secret string, enabled bool) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "update users set totp_secret = ?, totp_enabled = ? where id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, secret, enabled, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep records the time step of an accepted code and reports false when
+// that step (or a later one) was already used, which blocks replaying a code.
+func (u users) UseTOTPStep(ctx context.Context, ID uint64, step int64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "update users set totp_last_step = ? where id = ? and totp_last_step < ?")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, step, ID, step)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (u users) SearchAccounts(ctx context.Context, params pagination.Params) ([]models.User, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("createdat", "id")
+
+	rows, err := u.db.QueryContext(ctx,
+		"select id, name, nick, email, email_verified, role, suspended_at, createdat from users where "+
+			condition+" "+params.OrderBy("createdat", "id"),
+		arguments...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
 		if err = rows.Scan(
-			&user.Password,
+			&user.ID,
+			&user.Name,
+			&user.Nick,
+			&user.Email,
+			&user.EmailVerified,
+			&user.Role,
+			&user.SuspendedAt,
+			&user.CreatedAt,
 		); err != nil {
-			return "", err
+			return nil, err
 		}
+
+		users = append(users, user)
 	}
 
-	return user.Password, nil
+	return users, nil
+}
+
+func (u users) UpdateRole(ctx context.Context, ID uint64, role string) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "update users set role = ? where id = ?")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, role, ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+// Suspend also revokes every session of a suspended user, in the same
+// transaction, so its refresh tokens stop working right away.
+func (u users) Suspend(ctx context.Context, ID uint64, suspended bool) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	if !suspended {
+		statement, err := u.db.PrepareContext(ctx, "update users set suspended_at = null where id = ?")
+		if err != nil {
+			return err
+		}
+		defer statement.Close()
+
+		if _, err = statement.ExecContext(ctx, ID); err != nil {
+			return translate(err)
+		}
+
+		return nil
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = suspendUser(ctx, tx, ID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// suspendUser suspends the user and revokes its sessions within the caller's
+// transaction, shared by account suspension and report decisions.
+func suspendUser(ctx context.Context, tx *sql.Tx, ID uint64) error {
+	if _, err := tx.ExecContext(ctx, "update users set suspended_at = coalesce(suspended_at, now()) where id = ?", ID); err != nil {
+		return translate(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "update sessions set revoked_at = now() where user_id = ? and revoked_at is null", ID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+// UpdatePrivate turns pending follow requests into followers when the account
+// becomes public, since nobody is left to approve them.
+func (u users) UpdatePrivate(ctx context.Context, ID uint64, private bool) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "update users set private = ? where id = ?", private, ID); err != nil {
+		return translate(err)
+	}
+
+	if !private {
+		if _, err = tx.ExecContext(ctx, `
+			insert ignore into followers (user_id, follower_id)
+			select user_id, follower_id from follow_requests where user_id = ?
+		`, ID); err != nil {
+			return translate(err)
+		}
+
+		if _, err = tx.ExecContext(ctx, "delete from follow_requests where user_id = ?", ID); err != nil {
+			return translate(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (u users) IsFollowing(ctx context.Context, userID, followerID uint64) (bool, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	rows, err := u.db.QueryContext(ctx, "select 1 from followers where user_id = ? and follower_id = ?", userID, followerID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+
+	return true, nil
+}
+
+func (u users) RequestFollow(ctx context.Context, userID, followerID uint64) error {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "insert ignore into follow_requests (user_id, follower_id) values (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	if _, err = statement.ExecContext(ctx, userID, followerID); err != nil {
+		return translate(err)
+	}
+
+	return nil
+}
+
+func (u users) SearchFollowRequests(ctx context.Context, userID uint64, params pagination.Params) ([]models.FollowRequest, error) {
+	ctx, cancel := withReadTimeout(ctx)
+	defer cancel()
+
+	condition, arguments := params.Where("r.createdat", "r.follower_id")
+
+	rows, err := u.db.QueryContext(ctx, `
+		select r.user_id, r.follower_id, u.name, u.nick, r.createdat
+		from follow_requests r
+		inner join users u on u.id = r.follower_id
+		where r.user_id = ? and `+condition+" "+params.OrderBy("r.createdat", "r.follower_id"),
+		append([]interface{}{userID}, arguments...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []models.FollowRequest
+	for rows.Next() {
+		var request models.FollowRequest
+		if err = rows.Scan(
+			&request.UserID,
+			&request.FollowerID,
+			&request.FollowerName,
+			&request.FollowerNick,
+			&request.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func (u users) ApproveFollowRequest(ctx context.Context, userID, followerID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "delete from follow_requests where user_id = ? and follower_id = ?", userID, followerID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if _, err = tx.ExecContext(ctx, "insert ignore into followers (user_id, follower_id) values (?, ?)", userID, followerID); err != nil {
+		return false, translate(err)
+	}
+
+	return true, tx.Commit()
+}
+
+func (u users) DeleteFollowRequest(ctx context.Context, userID, followerID uint64) (bool, error) {
+	ctx, cancel := withWriteTimeout(ctx)
+	defer cancel()
+
+	statement, err := u.db.PrepareContext(ctx, "delete from follow_requests where user_id = ? and follower_id = ?")
+	if err != nil {
+		return false, err
+	}
+	defer statement.Close()
+
+	result, err := statement.ExecContext(ctx, userID, followerID)
+	if err != nil {
+		return false, translate(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected == 1, nil
 }
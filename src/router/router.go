@@ -1,12 +1,53 @@
 package router
 
 import (
+	"api/src/controllers"
+	"api/src/mail"
+	"api/src/middlewares"
+	"api/src/oidc"
+	"api/src/repositories"
 	router "api/src/router/routers"
+	"database/sql"
 
 	"github.com/gorilla/mux"
 )
 
-func Generate() *mux.Router {
+func Generate(db *sql.DB, mailer mail.Mailer) *mux.Router {
+	users := repositories.NewRepositoryUsers(db)
+	sessions := repositories.NewRepositorySessions(db)
+	publications := repositories.NewRepositoryPublications(db)
+	comments := repositories.NewRepositoryComments(db)
+	passwordResets := repositories.NewRepositoryPasswordResets(db)
+	emailVerifications := repositories.NewRepositoryEmailVerifications(db)
+	loginChallenges := repositories.NewRepositoryLoginChallenges(db)
+	recoveryCodes := repositories.NewRepositoryRecoveryCodes(db)
+	loginAttempts := repositories.NewRepositoryLoginAttempts(db)
+	personalAccessTokens := repositories.NewRepositoryPersonalAccessTokens(db)
+	oauthClients := repositories.NewRepositoryOAuthClients(db)
+	oauthCodes := repositories.NewRepositoryOAuthCodes(db)
+	oauthConsents := repositories.NewRepositoryOAuthConsents(db)
+	oauthTokens := repositories.NewRepositoryOAuthTokens(db)
+	oidcLogins := repositories.NewRepositoryOIDCLogins(db)
+	userIdentities := repositories.NewRepositoryUserIdentities(db)
+	magicLinks := repositories.NewRepositoryMagicLinks(db)
+	blocks := repositories.NewRepositoryBlocks(db)
+	mutes := repositories.NewRepositoryMutes(db)
+	reports := repositories.NewRepositoryReports(db)
+
 	r := mux.NewRouter()
-	return router.Configure(r)
+	return router.Configure(r, router.Controllers{
+		Users:        controllers.NewControllerUsers(users, sessions, personalAccessTokens, oauthTokens, oauthConsents, emailVerifications, blocks, mutes, mailer),
+		Login:        controllers.NewControllerLogin(users, sessions, loginChallenges, recoveryCodes, loginAttempts, oidcLogins, userIdentities, oidc.New(), magicLinks, mailer),
+		Publications: controllers.NewControllerPublications(publications),
+		Comments:     controllers.NewControllerComments(comments, publications),
+		Keys:         controllers.NewControllerKeys(),
+		Passwords:    controllers.NewControllerPasswords(users, passwordResets, sessions, personalAccessTokens, oauthTokens, oauthConsents, mailer),
+		Emails:       controllers.NewControllerEmails(users, emailVerifications, mailer),
+		TwoFactor:    controllers.NewControllerTwoFactor(users, recoveryCodes),
+		Admin:        controllers.NewControllerAdmin(users, sessions, publications),
+		Sessions:     controllers.NewControllerSessions(sessions),
+		Tokens:       controllers.NewControllerTokens(personalAccessTokens),
+		OAuth:        controllers.NewControllerOAuth(oauthClients, oauthCodes, oauthConsents, oauthTokens, users),
+		Reports:      controllers.NewControllerReports(reports, users, publications),
+	}, middlewares.NewAuthenticator(sessions, users, personalAccessTokens, oauthTokens))
 }
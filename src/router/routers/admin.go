@@ -0,0 +1,47 @@
+package router
+
+import (
+	"api/src/authentication"
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesAdmin(controller *controllers.Admin) []Route {
+	return []Route{
+		{
+			URI:                    "/admin/users",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchAccounts,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionListAccounts,
+		},
+		{
+			URI:                    "/admin/users/{userId}/suspension",
+			Methods:                http.MethodPost,
+			Function:               controller.SuspendUser,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionSuspendAccounts,
+		},
+		{
+			URI:                    "/admin/users/{userId}/suspension",
+			Methods:                http.MethodDelete,
+			Function:               controller.UnsuspendUser,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionSuspendAccounts,
+		},
+		{
+			URI:                    "/admin/users/{userId}/role",
+			Methods:                http.MethodPut,
+			Function:               controller.UpdateRole,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionManageRoles,
+		},
+		{
+			URI:                    "/admin/publications/{publicationId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.DeletePublication,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionDeleteAnyPublication,
+		},
+	}
+}
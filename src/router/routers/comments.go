@@ -0,0 +1,49 @@
+package router
+
+import (
+	"api/src/authentication"
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesComments(controller *controllers.Comments) []Route {
+	return []Route{
+		{
+			URI:                    "/publications/{publicationId}/comments",
+			Methods:                http.MethodPost,
+			Function:               controller.CreateComment,
+			RequiresAuthentication: true,
+			RequiresVerifiedEmail:  true,
+			Scope:                  authentication.ScopeCommentsWrite,
+		},
+		{
+			URI:                    "/publications/{publicationId}/comments",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchComments,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeCommentsRead,
+		},
+		{
+			URI:                    "/publications/{publicationId}/comments/{commentId}/replies",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchReplies,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeCommentsRead,
+		},
+		{
+			URI:                    "/publications/{publicationId}/comments/{commentId}",
+			Methods:                http.MethodPut,
+			Function:               controller.UpdateComment,
+			RequiresAuthentication: true,
+			RequiresVerifiedEmail:  true,
+			Scope:                  authentication.ScopeCommentsWrite,
+		},
+		{
+			URI:                    "/publications/{publicationId}/comments/{commentId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.DeleteComment,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeCommentsWrite,
+		},
+	}
+}
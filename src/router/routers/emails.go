@@ -0,0 +1,23 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesEmails(controller *controllers.Emails) []Route {
+	return []Route{
+		{
+			URI:                    "/email/verify",
+			Methods:                http.MethodPost,
+			Function:               controller.VerifyEmail,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/users/{userId}/email/verification",
+			Methods:                http.MethodPost,
+			Function:               controller.SendVerification,
+			RequiresAuthentication: true,
+		},
+	}
+}
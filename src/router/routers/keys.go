@@ -0,0 +1,17 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesKeys(controller *controllers.Keys) []Route {
+	return []Route{
+		{
+			URI:                    "/.well-known/jwks.json",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchKeys,
+			RequiresAuthentication: false,
+		},
+	}
+}
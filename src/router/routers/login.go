@@ -5,9 +5,55 @@ import (
 	"net/http"
 )
 
-var routerLogin = Route{
-	URI:                    "/login",
-	Methods:                http.MethodPost,
-	Function:               controllers.Login,
-	RequiresAuthentication: false,
+func routesLogin(controller *controllers.Login) []Route {
+	return []Route{
+		{
+			URI:                    "/login",
+			Methods:                http.MethodPost,
+			Function:               controller.Login,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/login/2fa",
+			Methods:                http.MethodPost,
+			Function:               controller.LoginTwoFactor,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/login/magic",
+			Methods:                http.MethodPost,
+			Function:               controller.SendMagicLink,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/login/magic/verify",
+			Methods:                http.MethodPost,
+			Function:               controller.LoginMagicLink,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/login/oidc",
+			Methods:                http.MethodGet,
+			Function:               controller.StartOIDC,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/login/oidc/callback",
+			Methods:                http.MethodPost,
+			Function:               controller.OIDCCallback,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/token/refresh",
+			Methods:                http.MethodPost,
+			Function:               controller.RefreshToken,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/logout",
+			Methods:                http.MethodPost,
+			Function:               controller.Logout,
+			RequiresAuthentication: true,
+		},
+	}
 }
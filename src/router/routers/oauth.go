@@ -0,0 +1,71 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesOAuth(controller *controllers.OAuth) []Route {
+	return []Route{
+		{
+			URI:                    "/users/{userId}/clients",
+			Methods:                http.MethodPost,
+			Function:               controller.RegisterClient,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/clients",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchClients,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/clients/{clientId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.DeleteClient,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/consents",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchConsents,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/consents/{clientId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.RevokeConsent,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/oauth/authorize",
+			Methods:                http.MethodGet,
+			Function:               controller.Authorize,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/oauth/authorize",
+			Methods:                http.MethodPost,
+			Function:               controller.Decide,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/oauth/token",
+			Methods:                http.MethodPost,
+			Function:               controller.Token,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/oauth/introspect",
+			Methods:                http.MethodPost,
+			Function:               controller.Introspect,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/oauth/revoke",
+			Methods:                http.MethodPost,
+			Function:               controller.Revoke,
+			RequiresAuthentication: false,
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesPasswords(controller *controllers.Passwords) []Route {
+	return []Route{
+		{
+			URI:                    "/password/forgot",
+			Methods:                http.MethodPost,
+			Function:               controller.ForgotPassword,
+			RequiresAuthentication: false,
+		},
+		{
+			URI:                    "/password/reset",
+			Methods:                http.MethodPost,
+			Function:               controller.ResetPassword,
+			RequiresAuthentication: false,
+		},
+	}
+}
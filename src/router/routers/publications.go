@@ -1,57 +1,78 @@
 package router
 
 import (
+	"api/src/authentication"
 	"api/src/controllers"
 	"net/http"
 )
 
-var routerPublication = []Route{
-	{
-		URI:                    "/publications",
-		Methods:                http.MethodPost,
-		Function:               controllers.CreatePublication,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/publications",
-		Methods:                http.MethodGet,
-		Function:               controllers.SearchPublications,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/publications/{publicationId}",
-		Methods:                http.MethodPost,
-		Function:               controllers.SearchPublication,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/publications/{publicationId}",
-		Methods:                http.MethodPut,
-		Function:               controllers.UpdatePublication,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/publications/{publicationId}",
-		Methods:                http.MethodDelete,
-		Function:               controllers.DeletePublication,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "users/{usersId}/publications",
-		Methods:                http.MethodGet,
-		Function:               controllers.SearchPublicationsUser,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "publications/{publicationId}/like",
-		Methods:                http.MethodPost,
-		Function:               controllers.LikePublication,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "publications/{publicationId}/dislike",
-		Methods:                http.MethodPost,
-		Function:               controllers.DislikePublication,
-		RequiresAuthentication: true,
-	},
+func routesPublications(controller *controllers.Publications) []Route {
+	return []Route{
+		{
+			URI:                    "/publications",
+			Methods:                http.MethodPost,
+			Function:               controller.CreatePublication,
+			RequiresAuthentication: true,
+			RequiresVerifiedEmail:  true,
+			Scope:                  authentication.ScopePublicationsWrite,
+		},
+		{
+			URI:                    "/publications",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchPublications,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsRead,
+		},
+		{
+			URI:                    "/publications/{publicationId}",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchPublication,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsRead,
+		},
+		{
+			URI:                    "/publications/{publicationId}",
+			Methods:                http.MethodPut,
+			Function:               controller.UpdatePublication,
+			RequiresAuthentication: true,
+			RequiresVerifiedEmail:  true,
+			Scope:                  authentication.ScopePublicationsWrite,
+		},
+		{
+			URI:                    "/publications/{publicationId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.DeletePublication,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsWrite,
+		},
+		{
+			URI:                    "/users/{userId}/publications",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchPublicationsUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsRead,
+		},
+		{
+			URI:                    "/publications/{publicationId}/like",
+			Methods:                http.MethodPost,
+			Function:               controller.LikePublication,
+			RequiresAuthentication: true,
+			RequiresVerifiedEmail:  true,
+			Scope:                  authentication.ScopePublicationsWrite,
+		},
+		{
+			URI:                    "/publications/{publicationId}/like",
+			Methods:                http.MethodDelete,
+			Function:               controller.UnlikePublication,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsWrite,
+		},
+		{
+			URI:                    "/publications/{publicationId}/likes",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchLikes,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsRead,
+		},
+	}
 }
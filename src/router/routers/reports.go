@@ -0,0 +1,47 @@
+package router
+
+import (
+	"api/src/authentication"
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesReports(controller *controllers.Reports) []Route {
+	return []Route{
+		{
+			URI:                    "/publications/{publicationId}/reports",
+			Methods:                http.MethodPost,
+			Function:               controller.ReportPublication,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopePublicationsWrite,
+		},
+		{
+			URI:                    "/users/{userId}/reports",
+			Methods:                http.MethodPost,
+			Function:               controller.ReportUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/admin/reports",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchReports,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionModerateReports,
+		},
+		{
+			URI:                    "/admin/reports/{reportId}",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchReport,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionModerateReports,
+		},
+		{
+			URI:                    "/admin/reports/{reportId}/decision",
+			Methods:                http.MethodPost,
+			Function:               controller.DecideReport,
+			RequiresAuthentication: true,
+			Permission:             authentication.PermissionModerateReports,
+		},
+	}
+}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"api/src/config"
+	"api/src/controllers"
 	"api/src/middlewares"
 	"net/http"
 
@@ -12,19 +14,57 @@ type Route struct {
 	Methods                string
 	Function               func(http.ResponseWriter, *http.Request)
 	RequiresAuthentication bool
+	RequiresVerifiedEmail  bool
+	Permission             string
+	Scope                  string
 }
 
-func Configure(r *mux.Router) *mux.Router {
-	router := routerUser
-	router = append(router, routerLogin)
-	router = append(router, routerPublication...)
+type Controllers struct {
+	Users        *controllers.Users
+	Login        *controllers.Login
+	Publications *controllers.Publications
+	Comments     *controllers.Comments
+	Keys         *controllers.Keys
+	Passwords    *controllers.Passwords
+	Emails       *controllers.Emails
+	TwoFactor    *controllers.TwoFactor
+	Admin        *controllers.Admin
+	Sessions     *controllers.Sessions
+	Tokens       *controllers.Tokens
+	OAuth        *controllers.OAuth
+	Reports      *controllers.Reports
+}
+
+func Configure(r *mux.Router, c Controllers, authenticator *middlewares.Authenticator) *mux.Router {
+	router := routesUsers(c.Users)
+	router = append(router, routesLogin(c.Login)...)
+	router = append(router, routesPublications(c.Publications)...)
+	router = append(router, routesComments(c.Comments)...)
+	router = append(router, routesKeys(c.Keys)...)
+	router = append(router, routesPasswords(c.Passwords)...)
+	router = append(router, routesEmails(c.Emails)...)
+	router = append(router, routesTwoFactor(c.TwoFactor)...)
+	router = append(router, routesAdmin(c.Admin)...)
+	router = append(router, routesSessions(c.Sessions)...)
+	router = append(router, routesTokens(c.Tokens)...)
+	router = append(router, routesOAuth(c.OAuth)...)
+	router = append(router, routesReports(c.Reports)...)
 
 	for _, route := range router {
+		function := route.Function
+		if route.RequiresVerifiedEmail && config.RequireVerifiedEmail {
+			function = authenticator.RequireVerifiedEmail(function)
+		}
+
+		if route.Permission != "" {
+			function = authenticator.Authorize(route.Permission, function)
+		}
 
-		if route.RequiresAuthentication {
-			r.HandleFunc(route.URI, middlewares.Logger(middlewares.Authenticate(route.Function))).Methods(route.Methods)
+		if route.RequiresAuthentication || route.Permission != "" {
+			function = authenticator.RequireScope(route.Scope, function)
+			r.HandleFunc(route.URI, middlewares.Logger(authenticator.Authenticate(function))).Methods(route.Methods)
 		} else {
-			r.HandleFunc(route.URI, middlewares.Logger(route.Function)).Methods(route.Methods)
+			r.HandleFunc(route.URI, middlewares.Logger(function)).Methods(route.Methods)
 		}
 
 	}
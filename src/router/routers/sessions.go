@@ -0,0 +1,23 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesSessions(controller *controllers.Sessions) []Route {
+	return []Route{
+		{
+			URI:                    "/users/{userId}/sessions",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchSessions,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/sessions/{sessionId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.RevokeSession,
+			RequiresAuthentication: true,
+		},
+	}
+}
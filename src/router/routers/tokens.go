@@ -0,0 +1,29 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesTokens(controller *controllers.Tokens) []Route {
+	return []Route{
+		{
+			URI:                    "/users/{userId}/tokens",
+			Methods:                http.MethodPost,
+			Function:               controller.CreateToken,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/tokens",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchTokens,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/tokens/{tokenId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.RevokeToken,
+			RequiresAuthentication: true,
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+func routesTwoFactor(controller *controllers.TwoFactor) []Route {
+	return []Route{
+		{
+			URI:                    "/users/{userId}/2fa",
+			Methods:                http.MethodPost,
+			Function:               controller.EnrollTwoFactor,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/2fa/confirm",
+			Methods:                http.MethodPost,
+			Function:               controller.ConfirmTwoFactor,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/2fa/disable",
+			Methods:                http.MethodPost,
+			Function:               controller.DisableTwoFactor,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/2fa/recovery-codes",
+			Methods:                http.MethodPost,
+			Function:               controller.RegenerateRecoveryCodes,
+			RequiresAuthentication: true,
+		},
+	}
+}
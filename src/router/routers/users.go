@@ -1,69 +1,150 @@
 package router
 
 import (
+	"api/src/authentication"
 	"api/src/controllers"
 	"net/http"
 )
 
-var routerUser = []Route{
-	{
-		URI:                    "/users",
-		Methods:                http.MethodPost,
-		Function:               controllers.CreateUser,
-		RequiresAuthentication: false, // Temporary test
-	},
-	{
-		URI:                    "/users",
-		Methods:                http.MethodGet,
-		Function:               controllers.SearchUsers,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}",
-		Methods:                http.MethodGet,
-		Function:               controllers.SearchUser,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}",
-		Methods:                http.MethodPut,
-		Function:               controllers.UpdateUser,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}",
-		Methods:                http.MethodDelete,
-		Function:               controllers.DeleteUser,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}/seguir",
-		Methods:                http.MethodPost,
-		Function:               controllers.FollowUser,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}/parar-de-seguir",
-		Methods:                http.MethodPost,
-		Function:               controllers.UnfollowollowUser,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}/followers",
-		Methods:                http.MethodPost,
-		Function:               controllers.SearchFollowers,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}/following",
-		Methods:                http.MethodPost,
-		Function:               controllers.SearchFollowing,
-		RequiresAuthentication: true,
-	},
-	{
-		URI:                    "/users/{userId}/update-password",
-		Methods:                http.MethodPost,
-		Function:               controllers.UpdatePassword,
-		RequiresAuthentication: true,
-	},
+func routesUsers(controller *controllers.Users) []Route {
+	return []Route{
+		{
+			URI:                    "/users",
+			Methods:                http.MethodPost,
+			Function:               controller.CreateUser,
+			RequiresAuthentication: false, // Temporary test
+		},
+		{
+			URI:                    "/users",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchUsers,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}",
+			Methods:                http.MethodPut,
+			Function:               controller.UpdateUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.DeleteUser,
+			RequiresAuthentication: true,
+		},
+		{
+			URI:                    "/users/{userId}/seguir",
+			Methods:                http.MethodPost,
+			Function:               controller.FollowUser,
+			RequiresAuthentication: true,
+			RequiresVerifiedEmail:  true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/parar-de-seguir",
+			Methods:                http.MethodPost,
+			Function:               controller.UnfollowollowUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/private",
+			Methods:                http.MethodPut,
+			Function:               controller.UpdatePrivacy,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/follow-requests",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchFollowRequests,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}/follow-requests/{followerId}/approve",
+			Methods:                http.MethodPost,
+			Function:               controller.ApproveFollowRequest,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/follow-requests/{followerId}",
+			Methods:                http.MethodDelete,
+			Function:               controller.RejectFollowRequest,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/followers",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchFollowers,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}/following",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchFollowing,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}/block",
+			Methods:                http.MethodPost,
+			Function:               controller.BlockUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/block",
+			Methods:                http.MethodDelete,
+			Function:               controller.UnblockUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/blocks",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchBlocked,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}/mute",
+			Methods:                http.MethodPost,
+			Function:               controller.MuteUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/mute",
+			Methods:                http.MethodDelete,
+			Function:               controller.UnmuteUser,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersWrite,
+		},
+		{
+			URI:                    "/users/{userId}/mutes",
+			Methods:                http.MethodGet,
+			Function:               controller.SearchMuted,
+			RequiresAuthentication: true,
+			Scope:                  authentication.ScopeUsersRead,
+		},
+		{
+			URI:                    "/users/{userId}/update-password",
+			Methods:                http.MethodPost,
+			Function:               controller.UpdatePassword,
+			RequiresAuthentication: true,
+		},
+	}
 }
@@ -0,0 +1,50 @@
+package security
+
+import (
+	"api/src/config"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// maxIPLength is the size of the ip columns, enough for any IPv6 address.
+const maxIPLength = 45
+
+// ClientIP only trusts X-Forwarded-For when the API is configured to run behind
+// a proxy, since any client can send the header. Even then only the rightmost
+// entry is used: it is the one the proxy appended, while everything before it
+// came from the client.
+func ClientIP(r *http.Request) string {
+	if config.TrustProxyHeaders {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			entries := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := net.ParseIP(strings.TrimSpace(entries[len(entries)-1])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String()
+	}
+
+	if len(host) > maxIPLength {
+		host = host[:maxIPLength]
+	}
+
+	return host
+}
+
+func ClientUserAgent(r *http.Request) string {
+	userAgent := []rune(r.UserAgent())
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	return string(userAgent)
+}
@@ -0,0 +1,51 @@
+package security
+
+import (
+	"api/src/config"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	defer func(trust bool) { config.TrustProxyHeaders = trust }(config.TrustProxyHeaders)
+
+	tests := []struct {
+		name       string
+		trust      bool
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"remote address", false, "203.0.113.7:5123", nil, "203.0.113.7"},
+		{"ipv6 remote address", false, "[2001:db8::1]:5123", nil, "2001:db8::1"},
+		{"header without a trusted proxy", false, "203.0.113.7:5123", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"single hop", true, "10.0.0.2:5123", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed leftmost entry", true, "10.0.0.2:5123", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"several hops", true, "10.0.0.2:5123", []string{"1.2.3.4, 192.0.2.9 ,198.51.100.1 "}, "198.51.100.1"},
+		{"repeated headers", true, "10.0.0.2:5123", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"ipv6 entry", true, "10.0.0.2:5123", []string{"2001:DB8::2"}, "2001:db8::2"},
+		{"garbage entry", true, "10.0.0.2:5123", []string{"198.51.100.1, not an address"}, "10.0.0.2"},
+		{"entry with a port", true, "10.0.0.2:5123", []string{"198.51.100.1:80"}, "10.0.0.2"},
+		{"empty entry", true, "10.0.0.2:5123", []string{"198.51.100.1,"}, "10.0.0.2"},
+		{"overlong entry", true, "10.0.0.2:5123", []string{strings.Repeat("9", 1000)}, "10.0.0.2"},
+		{"remote address without port", false, "203.0.113.7", nil, "203.0.113.7"},
+		{"overlong remote address", false, strings.Repeat("x", 100), nil, strings.Repeat("x", 45)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config.TrustProxyHeaders = test.trust
+
+			r := httptest.NewRequest("POST", "/login", nil)
+			r.RemoteAddr = test.remoteAddr
+			for _, forwarded := range test.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			if got := ClientIP(r); got != test.want {
+				t.Errorf("ClientIP = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
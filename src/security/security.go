@@ -1,6 +1,17 @@
 package security
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -11,3 +22,105 @@ func HashPassword(password string) ([]byte, error) {
 func PasswordCheck(passwordWithHash, passwordString string) error {
 	return bcrypt.CompareHashAndPassword([]byte(passwordWithHash), []byte(passwordString))
 }
+
+var (
+	dummyPasswordHash []byte
+	dummyPasswordOnce sync.Once
+)
+
+// PasswordCheckUnknown spends the same time as PasswordCheck when there is no
+// account to check against, so response times do not reveal registered emails.
+func PasswordCheckUnknown(passwordString string) {
+	dummyPasswordOnce.Do(func() {
+		dummyPasswordHash, _ = HashPassword("password of an account that does not exist")
+	})
+
+	bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(passwordString))
+}
+
+func GenerateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// PKCEChallenge derives the S256 code challenge of a verifier (RFC 7636).
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyPKCE checks a code verifier against the S256 challenge sent when the
+// authorization code was requested.
+func VerifyPKCE(verifier, challenge string) bool {
+	return subtle.ConstantTimeCompare([]byte(PKCEChallenge(verifier)), []byte(challenge)) == 1
+}
+
+const encryptedPrefix = "enc:v1:"
+
+// Encrypt seals a value with AES-256-GCM under a key derived from the secret,
+// so it can be stored at rest without exposing it.
+func Encrypt(secret, value string) (string, error) {
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt with the same secret.
+func Decrypt(secret, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", errors.New("the value is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("the encrypted value is too short")
+	}
+
+	opened, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(opened), nil
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+func newAEAD(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,39 @@
+package security
+
+import "testing"
+
+// The code verifier and challenge of RFC 7636 appendix B.
+const (
+	rfc7636Verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfc7636Challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	if got := PKCEChallenge(rfc7636Verifier); got != rfc7636Challenge {
+		t.Errorf("PKCEChallenge = %q, want %q", got, rfc7636Challenge)
+	}
+}
+
+func TestVerifyPKCE(t *testing.T) {
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		want      bool
+	}{
+		{"rfc 7636 vector", rfc7636Verifier, rfc7636Challenge, true},
+		{"other verifier", rfc7636Verifier[1:], rfc7636Challenge, false},
+		{"plain method", rfc7636Verifier, rfc7636Verifier, false},
+		{"padded challenge", rfc7636Verifier, rfc7636Challenge + "=", false},
+		{"missing verifier", "", rfc7636Challenge, false},
+		{"missing challenge", rfc7636Verifier, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := VerifyPKCE(test.verifier, test.challenge); got != test.want {
+				t.Errorf("VerifyPKCE(%q, %q) = %v, want %v", test.verifier, test.challenge, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package security
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of RFC 6238 appendix B, "12345678901234567890", in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTPVectors(t *testing.T) {
+	// The RFC lists 8-digit codes; six-digit codes are their last six digits.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			now := time.Unix(test.unix, 0)
+
+			step, ok := ValidateTOTP(rfc6238Secret, test.code, now)
+			if !ok {
+				t.Fatalf("ValidateTOTP(%q) at %d was refused", test.code, test.unix)
+			}
+
+			if want := test.unix / totpPeriod; step != want {
+				t.Errorf("ValidateTOTP(%q) at %d matched step %d, want %d", test.code, test.unix, step, want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		now      time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfc6238Secret, "081804", now, step, true},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "081804", now, step, true},
+		{"surrounding spaces", rfc6238Secret, " 081804 ", now, step, true},
+		{"previous step", rfc6238Secret, "081804", now.Add(totpPeriod * time.Second), step, true},
+		{"next step", rfc6238Secret, "081804", now.Add(-totpPeriod * time.Second), step, true},
+		{"beyond the skew", rfc6238Secret, "081804", now.Add(2 * totpPeriod * time.Second), 0, false},
+		{"wrong code", rfc6238Secret, "081805", now, 0, false},
+		{"eight digits", rfc6238Secret, "07081804", now, 0, false},
+		{"empty code", rfc6238Secret, "", now, 0, false},
+		{"invalid secret", "not base32!", "081804", now, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotStep, ok := ValidateTOTP(test.secret, test.code, test.now)
+			if ok != test.wantOK || gotStep != test.wantStep {
+				t.Errorf("ValidateTOTP(%q, %q) = %d, %v, want %d, %v", test.secret, test.code, gotStep, ok, test.wantStep, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("GenerateTOTPSecret() = %q, want 20 bytes in unpadded base32", secret)
+	}
+
+	now := time.Now()
+	if _, ok := ValidateTOTP(secret, totpCode(key, now.Unix()/totpPeriod), now); !ok {
+		t.Errorf("the current code of a generated secret was refused")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(TOTPProvisioningURI("Social Network", "user@example.com", rfc6238Secret))
+	if err != nil {
+		t.Fatalf("TOTPProvisioningURI: %v", err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Social Network:user@example.com" {
+		t.Errorf("TOTPProvisioningURI = %s, want the otpauth://totp/issuer:account label", uri)
+	}
+
+	want := map[string]string{
+		"secret":    rfc6238Secret,
+		"issuer":    "Social Network",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	}
+	for parameter, value := range want {
+		if got := uri.Query().Get(parameter); got != value {
+			t.Errorf("%s = %q, want %q", parameter, got, value)
+		}
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes: %v", err)
+	}
+
+	if len(codes) != 10 {
+		t.Fatalf("GenerateRecoveryCodes(10) answered %d codes", len(codes))
+	}
+
+	format := regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("recovery code %q does not look like xxxxx-xxxxx", code)
+		}
+
+		if seen[code] {
+			t.Errorf("recovery code %q was generated twice", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	stored := HashRecoveryCode("abcde-fghij")
+
+	tests := []struct {
+		code      string
+		wantMatch bool
+	}{
+		{"abcde-fghij", true},
+		{"ABCDE-FGHIJ", true},
+		{"abcdefghij", true},
+		{"abcde fghij", true},
+		{" abcde-fghij ", true},
+		{"abcde-fghik", false},
+		{"abcde", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.code, func(t *testing.T) {
+			if match := HashRecoveryCode(test.code) == stored; match != test.wantMatch {
+				t.Errorf("HashRecoveryCode(%q) matched = %v, want %v", test.code, match, test.wantMatch)
+			}
+		})
+	}
+}